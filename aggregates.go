@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Summary holds aggregate figures for a result set, for -summary-only.
+type Summary struct {
+	Players          int            `json:"players"`
+	AveragePotential float64        `json:"average_potential"`
+	AverageGrowth    float64        `json:"average_growth"`
+	AverageAge       float64        `json:"average_age"`
+	BestPotential    int            `json:"best_potential"`
+	PerTeam          map[string]int `json:"per_team"` // Players kept per team.
+	OverallBands     []OverallBand  `json:"overall_bands"`
+}
+
+// overallBandWidth is the width of each overall-rating band.
+const overallBandWidth = 5
+
+// OverallBand counts the players whose overall rating is in From..To.
+type OverallBand struct {
+	From    int `json:"from"`
+	To      int `json:"to"`
+	Players int `json:"players"`
+}
+
+// overallBands buckets players by overall rating into bands of
+// overallBandWidth (60-64, 65-69, ...), lowest first. Bands between the
+// lowest and highest non-empty ones are included even when empty, so the
+// shape of the distribution is visible.
+func overallBands(players []Player) []OverallBand {
+	if len(players) == 0 {
+		return []OverallBand{}
+	}
+
+	band := func(overall int) int { return overall / overallBandWidth }
+	lo, hi := band(players[0].Overall), band(players[0].Overall)
+	for _, p := range players {
+		lo, hi = min(lo, band(p.Overall)), max(hi, band(p.Overall))
+	}
+
+	bands := make([]OverallBand, hi-lo+1)
+	for i := range bands {
+		from := (lo + i) * overallBandWidth
+		bands[i] = OverallBand{From: from, To: from + overallBandWidth - 1}
+	}
+	for _, p := range players {
+		bands[band(p.Overall)-lo].Players++
+	}
+	return bands
+}
+
+// formatBands renders bands as "60-64: 3, 65-69: 7".
+func formatBands(bands []OverallBand) string {
+	parts := make([]string, len(bands))
+	for i, b := range bands {
+		parts[i] = fmt.Sprintf("%d-%d: %d", b.From, b.To, b.Players)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// summarize computes the aggregate figures for players. The averages are
+// zero when there are no players.
+func summarize(players []Player) Summary {
+	sum := Summary{Players: len(players), PerTeam: make(map[string]int), OverallBands: overallBands(players)}
+	if len(players) == 0 {
+		return sum
+	}
+
+	var potential, growth, age int
+	for _, p := range players {
+		potential += p.Potential
+		growth += p.Growth
+		age += p.Age
+		sum.BestPotential = max(sum.BestPotential, p.Potential)
+		sum.PerTeam[p.Team]++
+	}
+	n := float64(len(players))
+	sum.AveragePotential = float64(potential) / n
+	sum.AverageGrowth = float64(growth) / n
+	sum.AverageAge = float64(age) / n
+	return sum
+}
+
+// logSummary logs the aggregate figures, with teams ordered by how many
+// players they contributed.
+func logSummary(sum Summary) {
+	teams := make([]string, 0, len(sum.PerTeam))
+	for team := range sum.PerTeam {
+		teams = append(teams, team)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		a, b := teams[i], teams[j]
+		if sum.PerTeam[a] != sum.PerTeam[b] {
+			return sum.PerTeam[a] > sum.PerTeam[b]
+		}
+		return a < b
+	})
+
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "players\t%d\n", sum.Players)
+	_, _ = fmt.Fprintf(tw, "average potential\t%.1f\n", sum.AveragePotential)
+	_, _ = fmt.Fprintf(tw, "average growth\t%.1f\n", sum.AverageGrowth)
+	_, _ = fmt.Fprintf(tw, "average age\t%.1f\n", sum.AverageAge)
+	_, _ = fmt.Fprintf(tw, "best potential\t%d\n", sum.BestPotential)
+	for _, band := range sum.OverallBands {
+		_, _ = fmt.Fprintf(tw, "overall %d-%d\t%d\n", band.From, band.To, band.Players)
+	}
+	for _, team := range teams {
+		_, _ = fmt.Fprintf(tw, "  %s\t%d\n", team, sum.PerTeam[team])
+	}
+	_ = tw.Flush()
+
+	log.Printf("Summary of the players found:\n%s", b.String())
+}
+
+// TeamSummary is one team's line in the -team-summary table.
+type TeamSummary struct {
+	Team             string  `json:"team"`
+	Prospects        int     `json:"prospects"`
+	AveragePotential float64 `json:"average_potential"`
+	MaxPotential     int     `json:"max_potential"`
+	TotalValue       int64   `json:"total_value"`        // Sum of the players' prices, in one currency.
+	Unvalued         int     `json:"unvalued,omitempty"` // Priced players left out of TotalValue: no rate to normalize with.
+}
+
+// aggregateByTeam computes one TeamSummary per team that has players, like
+// a league table: most prospects first, then the higher best potential, then
+// the team name. With normalized (-rates in use) TotalValue is in the base
+// currency, and players whose price could not be converted are counted as
+// Unvalued instead of being added in another currency; otherwise it sums the
+// prices as listed.
+func aggregateByTeam(players []Player, normalized bool) []TeamSummary {
+	index := make(map[string]int)
+	teams := []TeamSummary{}
+	potential := []int{}
+	for _, p := range players {
+		i, ok := index[p.Team]
+		if !ok {
+			i = len(teams)
+			index[p.Team] = i
+			teams = append(teams, TeamSummary{Team: p.Team})
+			potential = append(potential, 0)
+		}
+		t := &teams[i]
+		t.Prospects++
+		t.MaxPotential = max(t.MaxPotential, p.Potential)
+		potential[i] += p.Potential
+		switch {
+		case !normalized:
+			t.TotalValue += p.PriceValue
+		case p.PriceValue > 0 && p.PriceNormalized == 0:
+			t.Unvalued++
+		default:
+			t.TotalValue += p.PriceNormalized
+		}
+	}
+	for i := range teams {
+		teams[i].AveragePotential = float64(potential[i]) / float64(teams[i].Prospects)
+	}
+
+	sort.Slice(teams, func(i, j int) bool {
+		a, b := teams[i], teams[j]
+		if a.Prospects != b.Prospects {
+			return a.Prospects > b.Prospects
+		}
+		if a.MaxPotential != b.MaxPotential {
+			return a.MaxPotential > b.MaxPotential
+		}
+		return a.Team < b.Team
+	})
+	return teams
+}
+
+// writeAggregates saves the aggregate figures as indented JSON.
+func writeAggregates(path string, sum Summary) error {
+	return writeJSONFile(path, sum, "aggregates")
+}
+
+// writeTeamSummary saves the per-team table as indented JSON.
+func writeTeamSummary(path string, teams []TeamSummary) error {
+	return writeJSONFile(path, teams, "team summary")
+}
+
+// writeJSONFile saves v as indented JSON; what names it in errors.
+func writeJSONFile(path string, v any, what string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s to JSON: %w", what, err)
+	}
+	return writeFileAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
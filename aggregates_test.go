@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestOverallBands(t *testing.T) {
+	players := []Player{{Overall: 61}, {Overall: 64}, {Overall: 75}, {Overall: 60}, {Overall: 79}}
+	want := []OverallBand{
+		{From: 60, To: 64, Players: 3},
+		{From: 65, To: 69, Players: 0},
+		{From: 70, To: 74, Players: 0},
+		{From: 75, To: 79, Players: 2},
+	}
+	if got := overallBands(players); !slices.Equal(got, want) {
+		t.Errorf("overallBands() = %v, want %v", got, want)
+	}
+	if got := formatBands(want[:2]); got != "60-64: 3, 65-69: 0" {
+		t.Errorf("formatBands() = %q", got)
+	}
+}
+
+func TestOverallBandsEdges(t *testing.T) {
+	if got := overallBands(nil); got == nil || len(got) != 0 {
+		t.Errorf("no players: %v, want an empty list", got)
+	}
+	want := []OverallBand{{From: 65, To: 69, Players: 2}}
+	if got := overallBands([]Player{{Overall: 65}, {Overall: 69}}); !slices.Equal(got, want) {
+		t.Errorf("one band: %v, want %v", got, want)
+	}
+}
+
+func TestAggregateByTeam(t *testing.T) {
+	players := []Player{
+		{Team: "B", Potential: 80, PriceValue: 1000000},
+		{Team: "A", Potential: 75, PriceValue: 500000},
+		{Team: "B", Potential: 70, PriceValue: 0},
+		{Team: "C", Potential: 85, PriceValue: 2000000},
+		{Team: "A", Potential: 85, PriceValue: 300000},
+	}
+	want := []TeamSummary{
+		{Team: "A", Prospects: 2, AveragePotential: 80, MaxPotential: 85, TotalValue: 800000},
+		{Team: "B", Prospects: 2, AveragePotential: 75, MaxPotential: 80, TotalValue: 1000000},
+		{Team: "C", Prospects: 1, AveragePotential: 85, MaxPotential: 85, TotalValue: 2000000},
+	}
+	if got := aggregateByTeam(players, false); !slices.Equal(got, want) {
+		t.Errorf("aggregateByTeam() = %v, want %v", got, want)
+	}
+	if got := aggregateByTeam(nil, false); got == nil || len(got) != 0 {
+		t.Errorf("no players: %v, want an empty list", got)
+	}
+}
+
+// With -rates, a price that could not be normalized is left out of the total
+// instead of being added in its own currency.
+func TestAggregateByTeamNormalized(t *testing.T) {
+	players := []Player{
+		{Team: "A", Potential: 80, PriceValue: 1000000, PriceNormalized: 850000},
+		{Team: "A", Potential: 80, PriceValue: 900000},
+		{Team: "A", Potential: 80},
+	}
+	got := aggregateByTeam(players, true)
+	if len(got) != 1 || got[0].TotalValue != 850000 || got[0].Unvalued != 1 || got[0].Prospects != 3 {
+		t.Errorf("aggregateByTeam() = %+v, want a total of 850000 with 1 unvalued", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		players []Player
+		want    Summary
+	}{
+		{
+			name: "empty",
+			want: Summary{PerTeam: map[string]int{}},
+		},
+		{
+			name:    "one player",
+			players: []Player{{Team: "A", Potential: 80, Growth: 20, Age: 18, Overall: 60}},
+			want: Summary{
+				Players: 1, AveragePotential: 80, AverageGrowth: 20, AverageAge: 18, BestPotential: 80,
+				PerTeam: map[string]int{"A": 1},
+			},
+		},
+		{
+			name: "several teams",
+			players: []Player{
+				{Team: "A", Potential: 80, Growth: 20, Age: 18, Overall: 60},
+				{Team: "B", Potential: 75, Growth: 10, Age: 21, Overall: 65},
+				{Team: "A", Potential: 85, Growth: 15, Age: 17, Overall: 70},
+				{Team: "C", Potential: 72, Growth: 3, Age: 24, Overall: 69},
+			},
+			want: Summary{
+				Players: 4, AveragePotential: 78, AverageGrowth: 12, AverageAge: 20, BestPotential: 85,
+				PerTeam: map[string]int{"A": 2, "B": 1, "C": 1},
+			},
+		},
+	} {
+		got := summarize(tc.players)
+		if got.Players != tc.want.Players || got.BestPotential != tc.want.BestPotential ||
+			got.AveragePotential != tc.want.AveragePotential || got.AverageGrowth != tc.want.AverageGrowth || got.AverageAge != tc.want.AverageAge {
+			t.Errorf("%s: summarize() = %+v, want %+v", tc.name, got, tc.want)
+		}
+		if !maps.Equal(got.PerTeam, tc.want.PerTeam) {
+			t.Errorf("%s: per team = %v, want %v", tc.name, got.PerTeam, tc.want.PerTeam)
+		}
+		for _, v := range []float64{got.AveragePotential, got.AverageGrowth, got.AverageAge} {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				t.Errorf("%s: average %v", tc.name, v)
+			}
+		}
+		// The aggregates file is JSON, which has no NaN.
+		if _, err := json.Marshal(got); err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// basicAuth is a flag.Value holding "user:pass" credentials. Its String
+// method never reveals the password.
+type basicAuth struct {
+	user     string
+	password string
+	set      bool
+}
+
+func (a *basicAuth) String() string {
+	if !a.set {
+		return ""
+	}
+	return a.user + ":***"
+}
+
+func (a *basicAuth) Set(value string) error {
+	user, password, ok := strings.Cut(value, ":")
+	if !ok || user == "" {
+		return errors.New("want user:pass")
+	}
+	a.user, a.password, a.set = user, password, true
+	return nil
+}
+
+// applyBasicAuth adds the configured credentials to req when its host is one
+// of the -basic-auth-hosts. Other hosts, such as the public site or one
+// reached through a redirect, never see them.
+func (s *Scraper) applyBasicAuth(req *http.Request) {
+	if !s.basicAuth.set || !hostMatches(req.URL, s.basicAuthHosts) {
+		return
+	}
+	req.SetBasicAuth(s.basicAuth.user, s.basicAuth.password)
+}
+
+// hostMatches reports whether u's host, with or without its port, is in hosts.
+func hostMatches(u *url.URL, hosts []string) bool {
+	for _, h := range hosts {
+		if strings.EqualFold(h, u.Host) || strings.EqualFold(h, u.Hostname()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func authServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "scout" || pass != "s3cret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="mirror"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(rosterPage))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBasicAuth(t *testing.T) {
+	srv := authServer(t)
+	host, _ := url.Parse(srv.URL)
+
+	for _, tc := range []struct {
+		name   string
+		creds  string
+		hosts  []string
+		wantOK bool
+	}{
+		{"no credentials", "", nil, false},
+		{"wrong password", "scout:guess", []string{host.Hostname()}, false},
+		{"no hosts", "scout:s3cret", nil, false},
+		{"matching host", "scout:s3cret", []string{"mirror.example", host.Hostname()}, true},
+		{"matching host and port", "scout:s3cret", []string{host.Host}, true},
+		{"other host", "scout:s3cret", []string{"mirror.example"}, false},
+	} {
+		s := newTestScraper(t)
+		if tc.creds != "" {
+			if err := s.basicAuth.Set(tc.creds); err != nil {
+				t.Fatal(err)
+			}
+		}
+		s.basicAuthHosts = tc.hosts
+
+		_, _, err := s.fetchHTML(context.Background(), Team{Name: "A"}, srv.URL, nil)
+		if tc.wantOK && err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+		var se *statusError
+		if !tc.wantOK && (!errors.As(err, &se) || se.code != http.StatusUnauthorized) {
+			t.Errorf("%s: err = %v, want 401", tc.name, err)
+		}
+	}
+}
+
+func TestBasicAuthStringHidesPassword(t *testing.T) {
+	var a basicAuth
+	if err := a.Set("scout:s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.String(); got != "scout:***" {
+		t.Errorf("String() = %q", got)
+	}
+	if err := a.Set("nocolon"); err == nil {
+		t.Error("accepted credentials without a password separator")
+	}
+}
+
+func TestBasicAuthNeedsHosts(t *testing.T) {
+	s := newTestScraper(t)
+	if err := s.basicAuth.Set("scout:s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.validate(); err == nil {
+		t.Error("validate() accepted -basic-auth without -basic-auth-hosts")
+	}
+	s.basicAuthHosts = []string{"mirror.example"}
+	if err := s.validate(); err != nil {
+		t.Errorf("validate() = %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+)
+
+// resultHash returns a stable hash of a result set. Players are normalized
+// and sorted first, so collection order does not change the hash.
+func resultHash(players []Player) (string, error) {
+	normalized := make([]Player, len(players))
+	for i, p := range players {
+		p.Profile = strings.TrimSpace(p.Profile)
+		p.Team = strings.TrimSpace(p.Team)
+		normalized[i] = p
+	}
+	sort.SliceStable(normalized, func(i, j int) bool {
+		a, b := normalized[i], normalized[j]
+		if a.Team != b.Team {
+			return a.Team < b.Team
+		}
+		if a.Profile != b.Profile {
+			return a.Profile < b.Profile
+		}
+		return a.Overall < b.Overall
+	})
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// hashPath is the sidecar file holding the result hash for an output.
+func hashPath(output string) string {
+	return output + ".sha256"
+}
+
+// unchanged reports whether output exists and its sidecar holds hash.
+func unchanged(output, hash string) bool {
+	if _, err := os.Stat(output); err != nil {
+		return false
+	}
+	stored, err := os.ReadFile(hashPath(output))
+	return err == nil && strings.TrimSpace(string(stored)) == hash
+}
+
+// saveHash records hash in output's sidecar file.
+func saveHash(output, hash string) error {
+	if err := os.WriteFile(hashPath(output), []byte(hash+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write result hash: %w", err)
+	}
+	return nil
+}
+
+// forgetHash removes output's sidecar file, if any.
+func forgetHash(output string) error {
+	if err := os.Remove(hashPath(output)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove result hash: %w", err)
+	}
+	return nil
+}
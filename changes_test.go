@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestResultHashIgnoresOrder(t *testing.T) {
+	a := []Player{{Profile: "A", Team: "X"}, {Profile: "B", Team: "X"}}
+	b := []Player{{Profile: " B", Team: "X"}, {Profile: "A", Team: "X "}}
+	ha, err := resultHash(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hb, err := resultHash(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ha != hb {
+		t.Errorf("hashes differ: %s, %s", ha, hb)
+	}
+}
+
+// A live flush rewrites the output with partial results; the final write of
+// the same run must not then be skipped because the previous run's hash
+// still matches.
+func TestOnlyChangedAfterFlush(t *testing.T) {
+	s := newTestScraper(t)
+	s.onlyChanged = true
+	path := s.outputs[0]
+	full := []Player{{Profile: "A", Team: "X"}, {Profile: "B", Team: "X"}}
+
+	if err := s.writeOutputs(full); err != nil {
+		t.Fatal(err)
+	}
+	s.flushOutputs(full[:1])
+	if err := s.writeOutputs(full); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Player
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(full) {
+		t.Errorf("output holds %d players, want %d", len(got), len(full))
+	}
+
+	// With nothing flushed in between, the write is skipped again.
+	before, _ := os.Stat(path)
+	if err := s.writeOutputs(full); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.Stat(path)
+	if !after.ModTime().Equal(before.ModTime()) {
+		t.Error("unchanged output was rewritten")
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// decodeBody converts a response body to UTF-8 text. The encoding is found
+// the way a browser finds it: a byte-order mark, then the Content-Type
+// header, then a <meta charset> declaration near the top, and otherwise a
+// guess between UTF-8 and Windows-1252. Any BOM is stripped.
+func (s *Scraper) decodeBody(body []byte, contentType string) string {
+	e, name, _ := charset.DetermineEncoding(body, contentType)
+	text, err := e.NewDecoder().Bytes(body)
+	if err != nil {
+		s.debugf("Decoding the page as %s failed (%v); leaving the body as is\n", name, err)
+		text = body
+	}
+	return strings.TrimPrefix(string(text), "\ufeff")
+}
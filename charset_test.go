@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// latin1Page is a roster page encoded as ISO-8859-1: "José" is 'J','o','s',0xE9.
+var latin1Page = "<html><head><meta charset=\"iso-8859-1\"></head><table>\n" +
+	"<tr><td>Jos\xe9 P\xe9rez</td><td>60</td><td>80</td><td>20</td><td>18</td><td>\x801.2M</td></tr>\n" +
+	"</table></html>\n"
+
+func TestDecodeBodyLatin1(t *testing.T) {
+	s := newTestScraper(t)
+	for _, tc := range []struct{ name, contentType string }{
+		{"header", "text/html; charset=ISO-8859-1"},
+		{"meta", "text/html"},
+	} {
+		got := s.decodeBody([]byte(latin1Page), tc.contentType)
+		if !strings.Contains(got, "José Pérez") || !strings.Contains(got, "€1.2M") {
+			t.Errorf("%s: decoded to %q", tc.name, got)
+		}
+	}
+}
+
+// Any charset the page declares is decoded, not just the Western ones.
+func TestDecodeBodyCharsets(t *testing.T) {
+	s := newTestScraper(t)
+	for _, tc := range []struct{ contentType, body, want string }{
+		{"text/html; charset=windows-1250", "\xa3ukasz Nowak", "Łukasz Nowak"},
+		{"text/html; charset=iso-8859-2", "\xa3ukasz \xb1", "Łukasz ą"},
+		{"text/html; charset=Shift_JIS", "\x8eR\x93c", "山田"},
+		{"text/html", "<meta charset=\"koi8-r\">\xe9\xd7\xc1\xce", "<meta charset=\"koi8-r\">Иван"},
+		{"text/html; charset=utf-16le", "J\x00o\x00", "Jo"},
+		{"text/html", "\xff\xfeJ\x00o\x00", "Jo"},
+		{"text/html; charset=no-such-charset", "Jos\xc3\xa9 P\xc3\xa9rez", "José Pérez"},
+	} {
+		if got := s.decodeBody([]byte(tc.body), tc.contentType); got != tc.want {
+			t.Errorf("%s: decodeBody(%q) = %q, want %q", tc.contentType, tc.body, got, tc.want)
+		}
+	}
+}
+
+func TestDecodeBodyStripsBOM(t *testing.T) {
+	s := newTestScraper(t)
+	if got := s.decodeBody([]byte("\xef\xbb\xbf<html>é"), ""); got != "<html>é" {
+		t.Errorf("decodeBody() = %q", got)
+	}
+}
+
+// A Latin-1 page saved by -dump-dir must parse the same on replay as it did
+// when fetched.
+func TestReplayOfLatin1Dump(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		_, _ = w.Write([]byte(latin1Page))
+	}))
+	defer srv.Close()
+	team := Team{Name: "A", URL: srv.URL + "/team/a"}
+	dir := t.TempDir()
+
+	fetched := newTestScraper(t)
+	fetched.dumpDir = dir
+	html, _, err := fetched.loadPage(context.Background(), team, team.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live, _ := fetched.extractPlayers(team, html)
+
+	replayer := newTestScraper(t)
+	replayer.replayDir = dir
+	html, _, err = replayer.loadPage(context.Background(), team, team.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, _ := replayer.extractPlayers(team, html)
+
+	if len(live) != 1 || len(replayed) != 1 {
+		t.Fatalf("got %d live and %d replayed players, want 1 each", len(live), len(replayed))
+	}
+	if live[0].Profile != "José Pérez" || replayed[0].Profile != live[0].Profile {
+		t.Errorf("live %q, replayed %q, want %q", live[0].Profile, replayed[0].Profile, "José Pérez")
+	}
+}
+
+func TestCapturedHeadersDescribeSavedPage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		_, _ = w.Write([]byte(latin1Page))
+	}))
+	defer srv.Close()
+
+	s := newTestScraper(t)
+	s.fetchOnly = t.TempDir()
+	team := Team{Name: "A", URL: srv.URL + "/team/a"}
+	if _, err := s.capturePage(context.Background(), team, team.URL); err != nil {
+		t.Fatal(err)
+	}
+	headers, err := os.ReadFile(filepath.Join(s.fetchOnly, strings.TrimSuffix(dumpFileName(team, team.URL), ".html")+".headers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(headers); !strings.Contains(got, "Content-Type: text/html; charset=utf-8") || strings.Contains(got, "Content-Length") {
+		t.Errorf("saved headers:\n%s", got)
+	}
+}
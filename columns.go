@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// columnMap gives the cell index of each field in a roster row. A negative
+// index marks a column the page does not have.
+type columnMap struct {
+	Profile   int
+	Overall   int
+	Potential int
+	Growth    int
+	Age       int
+	Price     int
+	Contract  int // Not shown on the default roster view.
+	Height    int // Not shown on the default roster view.
+	Weight    int // Not shown on the default roster view.
+}
+
+// defaultColumns matches the roster table layout on fifacm.com.
+var defaultColumns = columnMap{
+	Profile:   0,
+	Overall:   1,
+	Potential: 2,
+	Growth:    3,
+	Age:       4,
+	Price:     5,
+	Contract:  -1,
+	Height:    -1,
+	Weight:    -1,
+}
+
+// fields returns the mapped indexes by field name, for parsing and printing.
+func (m *columnMap) fields() map[string]*int {
+	return map[string]*int{
+		"profile":   &m.Profile,
+		"overall":   &m.Overall,
+		"potential": &m.Potential,
+		"growth":    &m.Growth,
+		"age":       &m.Age,
+		"price":     &m.Price,
+		"contract":  &m.Contract,
+		"height":    &m.Height,
+		"weight":    &m.Weight,
+	}
+}
+
+// String lists the mapping as name=index pairs in a stable order.
+func (m *columnMap) String() string {
+	if m == nil {
+		return ""
+	}
+	var pairs []string
+	for name, idx := range m.fields() {
+		pairs = append(pairs, name+"="+strconv.Itoa(*idx))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set overrides individual indexes from a list like "price=6,age=5".
+// Fields that are not mentioned keep their current index.
+func (m *columnMap) Set(value string) error {
+	fields := m.fields()
+	for _, pair := range strings.Split(value, ",") {
+		name, idx, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return fmt.Errorf("invalid column mapping %q (want name=index)", pair)
+		}
+		target, known := fields[strings.ToLower(name)]
+		if !known {
+			return fmt.Errorf("unknown column %q", name)
+		}
+		n, err := strconv.Atoi(idx)
+		if err != nil {
+			return fmt.Errorf("invalid index for column %q: %w", name, err)
+		}
+		*target = n
+	}
+	return nil
+}
+
+// optionalColumns are the fields only some views show. Rows without them
+// are still player rows, with the fields left empty.
+var optionalColumns = map[string]bool{
+	"contract": true,
+	"height":   true,
+	"weight":   true,
+}
+
+// maxRequiredIndex returns the highest index mapped to a column every
+// player row has, leaving out the optional columns.
+func (m *columnMap) maxRequiredIndex() int {
+	highest := -1
+	for name, idx := range m.fields() {
+		if !optionalColumns[name] {
+			highest = max(highest, *idx)
+		}
+	}
+	return highest
+}
+
+// requiredCells is the number of cells a row needs to be treated as a
+// player row. Narrower rows are headers, footers or spacers. It comes from
+// the mandatory columns of the column map unless overridden with -min-cells.
+func (s *Scraper) requiredCells() int {
+	if s.minCells > 0 {
+		return s.minCells
+	}
+	return s.columns.maxRequiredIndex() + 1
+}
+
+// optionalCell is like cell for a column that pages may not have at all: an
+// unmapped column, or one a row is too short for, quietly yields "".
+func (s *Scraper) optionalCell(cols [][]string, field string, idx int) string {
+	if idx < 0 || idx >= len(cols) {
+		return ""
+	}
+	return s.cell(cols, field, idx)
+}
+
+// cell returns the tag-stripped text of the mapped column, or "" when the
+// index falls outside the row. Missing cells leave the field at its zero
+// value instead of reading a neighbouring column.
+func (s *Scraper) cell(cols [][]string, field string, idx int) string {
+	if idx < 0 || idx >= len(cols) {
+		s.debugf("Column %s (index %d) is missing from a row with %d cells\n", field, idx, len(cols))
+		return ""
+	}
+	return s.stripTags(cols[idx][1])
+}
@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+// sevenColumnPage has a position column after the name and a six-cell
+// footer row that must not be taken for a player.
+const sevenColumnPage = `<table>
+<tr><td>John Smith</td><td>ST</td><td>60</td><td>80</td><td>20</td><td>18</td><td>€1.2M</td></tr>
+<tr><td>Tom Long</td><td>CB</td><td>58</td><td>79</td><td>21</td><td>17</td><td>€900K</td></tr>
+<tr><td>Total</td><td>2</td><td>59</td><td>80</td><td>21</td><td>18</td></tr>
+</table>`
+
+func TestSevenColumnLayout(t *testing.T) {
+	s := newTestScraper(t)
+	if err := s.columns.Set("overall=2,potential=3,growth=4,age=5,price=6"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.requiredCells(); got != 7 {
+		t.Errorf("requiredCells() = %d, want 7", got)
+	}
+
+	players, rows := s.extractPlayers(Team{Name: "A"}, sevenColumnPage)
+	if rows != 2 || len(players) != 2 {
+		t.Fatalf("got %d players from %d rows, want 2 from 2", len(players), rows)
+	}
+	if p := players[0]; p.Overall != 60 || p.Potential != 80 || p.Age != 18 || p.PriceValue != 1200000 {
+		t.Errorf("parsed %s (price %d)", p, p.PriceValue)
+	}
+}
+
+// With the default mapping the first six cells of a seven-column row are
+// read; -min-cells=7 keeps the footer out.
+func TestMinCellsOverride(t *testing.T) {
+	s := newTestScraper(t)
+	s.minGrowth, s.minPotential = 0, 0
+	if _, rows := s.extractPlayers(Team{Name: "A"}, sevenColumnPage); rows != 3 {
+		t.Errorf("default: %d rows, want 3", rows)
+	}
+	s.minCells = 7
+	if _, rows := s.extractPlayers(Team{Name: "A"}, sevenColumnPage); rows != 2 {
+		t.Errorf("-min-cells=7: %d rows, want 2", rows)
+	}
+}
+
+func TestColumnMapSet(t *testing.T) {
+	m := defaultColumns
+	if err := m.Set("price=6, Age=5"); err != nil {
+		t.Fatal(err)
+	}
+	if m.Price != 6 || m.Age != 5 || m.Profile != 0 {
+		t.Errorf("got %s", m.String())
+	}
+	for _, bad := range []string{"price", "position=2", "price=x"} {
+		if err := m.Set(bad); err == nil {
+			t.Errorf("Set(%q) accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// measurePattern finds the number in a height or weight cell.
+var measurePattern = regexp.MustCompile(`\d+`)
+
+// contractYearPattern finds a four-digit year in text like "2027",
+// "Jun 30, 2027" or "30/06/2027".
+var contractYearPattern = regexp.MustCompile(`\b(19|20)\d{2}\b`)
+
+// parseContractYear extracts the expiry year from a contract cell, or
+// returns 0 when there is none.
+func parseContractYear(text string) int {
+	match := contractYearPattern.FindString(text)
+	if match == "" {
+		return 0
+	}
+	year, _ := strconv.Atoi(match)
+	return year
+}
+
+// parseMeasure extracts a height or weight from text like "185cm",
+// "185 cm" or "78kg" by dropping the unit. It returns 0 when the cell holds
+// no number.
+func parseMeasure(text string) int {
+	n, _ := strconv.Atoi(measurePattern.FindString(text))
+	return n
+}
@@ -0,0 +1,87 @@
+package main
+
+import "testing"
+
+func TestParseContractYear(t *testing.T) {
+	for text, want := range map[string]int{
+		"2027":         2027,
+		"Jun 30, 2027": 2027,
+		"30/06/2026":   2026,
+		"":             0,
+		"Loan":         0,
+	} {
+		if got := parseContractYear(text); got != want {
+			t.Errorf("parseContractYear(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestParseMeasure(t *testing.T) {
+	for text, want := range map[string]int{
+		"185cm":  185,
+		"185 cm": 185,
+		"78kg":   78,
+		"78":     78,
+		"-":      0,
+		"":       0,
+	} {
+		if got := parseMeasure(text); got != want {
+			t.Errorf("parseMeasure(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+// contractPage shows the contract, height and weight columns, but the last
+// row has none of them.
+const contractPage = `<table>
+<tr><td>John Smith</td><td>60</td><td>80</td><td>20</td><td>18</td><td>€1.2M</td><td>Jun 30, 2026</td><td>185cm</td><td>78 kg</td></tr>
+<tr><td>Tom Long</td><td>58</td><td>79</td><td>21</td><td>17</td><td>€900K</td><td>2029</td><td>191 cm</td><td>84kg</td></tr>
+<tr><td>Free Lad</td><td>50</td><td>72</td><td>22</td><td>16</td><td>Free</td></tr>
+</table>`
+
+func contractScraper(t *testing.T) *Scraper {
+	t.Helper()
+	s := newTestScraper(t)
+	s.columns.Contract, s.columns.Height, s.columns.Weight = 6, 7, 8
+	return s
+}
+
+func TestExtractContractAndMeasures(t *testing.T) {
+	s := contractScraper(t)
+	players, rows := s.extractPlayers(Team{Name: "A"}, contractPage)
+	if rows != 3 || len(players) != 3 {
+		t.Fatalf("got %d players from %d rows, want 3 from 3", len(players), rows)
+	}
+
+	john := players[0]
+	if john.ContractExpiry != "Jun 30, 2026" || john.ContractYear != 2026 {
+		t.Errorf("contract = %q (%d), want Jun 30, 2026 (2026)", john.ContractExpiry, john.ContractYear)
+	}
+	if john.Height != 185 || john.Weight != 78 {
+		t.Errorf("height/weight = %d/%d, want 185/78", john.Height, john.Weight)
+	}
+
+	// A row without the optional cells is still a player, with them empty.
+	free := players[2]
+	if free.Profile != "Free Lad" || free.ContractExpiry != "" || free.ContractYear != 0 || free.Height != 0 || free.Weight != 0 {
+		t.Errorf("row without optional cells parsed as %+v", free)
+	}
+}
+
+func TestContractBefore(t *testing.T) {
+	s := contractScraper(t)
+	s.contractBefore = 2027
+	players, _ := s.extractPlayers(Team{Name: "A"}, contractPage)
+	if got := profiles(players); len(got) != 1 || got[0] != "John Smith" {
+		t.Errorf("kept %v, want [John Smith]", got)
+	}
+}
+
+func TestMinHeight(t *testing.T) {
+	s := contractScraper(t)
+	s.minHeight = 190
+	players, _ := s.extractPlayers(Team{Name: "A"}, contractPage)
+	if got := profiles(players); len(got) != 1 || got[0] != "Tom Long" {
+		t.Errorf("kept %v, want [Tom Long]", got)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// currencyURL returns the team page URL with the currency parameter set.
+func (s *Scraper) currencyURL(base, currency string) (string, error) {
+	return buildURL(base, map[string]string{s.currencyParam: currency})
+}
+
+// extractPrices maps each player row's profile to its parsed price, without
+// applying any filters. It is used for the extra currencies of -currencies.
+func (s *Scraper) extractPrices(html string) map[string]int64 {
+	prices := make(map[string]int64)
+	_, _ = eachRow(strings.NewReader(html), func(row string) {
+		cols := cellPattern.FindAllStringSubmatch(row, -1)
+		if len(cols) < s.requiredCells() {
+			return
+		}
+		profile := s.cell(cols, "profile", s.columns.Profile)
+		if value, ok := parsePrice(s.cell(cols, "price", s.columns.Price)); ok {
+			prices[profile] = value
+		}
+	})
+	return prices
+}
+
+// fetchExtraPrices fetches the team once for every currency after the first
+// (or reads it back with -replay) and records each player's price in that
+// currency. A failed currency is
+// logged and left out. It returns the number of attempts made.
+func (s *Scraper) fetchExtraPrices(ctx context.Context, team Team, players []Player) int {
+	attempts := 0
+	for _, currency := range s.currencies[1:] {
+		pageURL, err := s.currencyURL(team.URL, currency)
+		if err != nil {
+			log.Printf("Skipping %s prices for %s: %v\n", currency, team.Name, err)
+			continue
+		}
+
+		html, n, err := s.loadPage(ctx, team, pageURL, nil)
+		attempts += n
+		if err != nil {
+			log.Printf("Error fetching %s prices for %s: %v\n", currency, team.Name, err)
+			continue
+		}
+
+		prices := s.extractPrices(html)
+		for i := range players {
+			if value, ok := prices[players[i].Profile]; ok {
+				players[i].Prices[currency] = value
+			}
+		}
+	}
+	return attempts
+}
+
+// formatPrices renders a price map as "EUR=1200000;GBP=1000000" for flat outputs.
+func formatPrices(prices map[string]int64) string {
+	pairs := make([]string, 0, len(prices))
+	for currency, value := range prices {
+		pairs = append(pairs, currency+"="+strconv.FormatInt(value, 10))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Deduplication modes accepted by -dedupe.
+const (
+	dedupePerTeam = "per-team" // One entry per player per team, so loans stay visible.
+	dedupeGlobal  = "global"   // One entry per player across all teams.
+	dedupeOff     = "off"      // Keep every row.
+)
+
+// validateDedupe checks that mode is one of the supported values.
+func validateDedupe(mode string) error {
+	switch mode {
+	case dedupePerTeam, dedupeGlobal, dedupeOff:
+		return nil
+	}
+	return fmt.Errorf("unknown -dedupe mode %q (want %s, %s or %s)", mode, dedupePerTeam, dedupeGlobal, dedupeOff)
+}
+
+// profileKey normalizes a player name for deduplication: case and runs of
+// whitespace are ignored.
+func profileKey(profile string) string {
+	return strings.ToLower(strings.Join(strings.Fields(profile), " "))
+}
+
+// dedupePlayers drops repeated players according to mode, keeping the first
+// occurrence in the current order. It returns the remaining players and the
+// number dropped.
+func dedupePlayers(players []Player, mode string) ([]Player, int) {
+	if mode == dedupeOff {
+		return players, 0
+	}
+
+	seen := make(map[string]bool, len(players))
+	kept := players[:0]
+	for _, p := range players {
+		key := profileKey(p.Profile)
+		if mode == dedupePerTeam {
+			key = p.Team + "\x00" + key
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		kept = append(kept, p)
+	}
+	return kept, len(players) - len(kept)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// loanPages lists Kid at his parent club, marked as a loan, and at the club
+// he is on loan at; Tom appears for both clubs without a marker.
+var loanPages = map[string]string{
+	"Parent": `<table>
+<tr><td>Kid Loan</td><td>55</td><td>78</td><td>23</td><td>17</td><td>€300K</td></tr>
+<tr><td>Tom Long</td><td>58</td><td>79</td><td>21</td><td>17</td><td>€900K</td></tr>
+</table>`,
+	"Borrower": `<table>
+<tr><td>Kid</td><td>55</td><td>78</td><td>23</td><td>17</td><td>€300K</td></tr>
+<tr><td>tom  long</td><td>58</td><td>79</td><td>21</td><td>17</td><td>€900K</td></tr>
+</table>`,
+}
+
+func collectLoanPages(t *testing.T) []Player {
+	t.Helper()
+	s := newTestScraper(t)
+	var all []Player
+	for _, team := range []string{"Parent", "Borrower"} {
+		players, _ := s.extractPlayers(Team{Name: team}, loanPages[team])
+		all = append(all, players...)
+	}
+	return all
+}
+
+func teamsAndProfiles(players []Player) []string {
+	out := make([]string, len(players))
+	for i, p := range players {
+		out[i] = p.Team + "/" + p.Profile
+	}
+	return out
+}
+
+func TestDedupeModes(t *testing.T) {
+	for mode, want := range map[string][]string{
+		// The loan filter has already dropped Kid's "Loan" row, so neither
+		// mode sees him twice; only Tom is listed by both clubs.
+		dedupePerTeam: {"Parent/Tom Long", "Borrower/Kid", "Borrower/tom  long"},
+		dedupeGlobal:  {"Parent/Tom Long", "Borrower/Kid"},
+		dedupeOff:     {"Parent/Tom Long", "Borrower/Kid", "Borrower/tom  long"},
+	} {
+		players, dropped := dedupePlayers(collectLoanPages(t), mode)
+		if got := teamsAndProfiles(players); !slices.Equal(got, want) {
+			t.Errorf("%s: kept %v, want %v", mode, got, want)
+		}
+		if dropped != 3-len(want) {
+			t.Errorf("%s: dropped %d, want %d", mode, dropped, 3-len(want))
+		}
+	}
+}
+
+func TestDedupePerTeamDropsRepeatsWithinTeam(t *testing.T) {
+	players := []Player{
+		{Profile: "Tom Long", Team: "A", Overall: 58},
+		{Profile: "TOM LONG", Team: "A", Overall: 59},
+		{Profile: "Tom Long", Team: "B", Overall: 58},
+	}
+	kept, dropped := dedupePlayers(players, dedupePerTeam)
+	if got := teamsAndProfiles(kept); dropped != 1 || !slices.Equal(got, []string{"A/Tom Long", "B/Tom Long"}) {
+		t.Errorf("kept %v (dropped %d)", got, dropped)
+	}
+}
+
+func TestValidateDedupe(t *testing.T) {
+	for _, mode := range []string{dedupePerTeam, dedupeGlobal, dedupeOff} {
+		if err := validateDedupe(mode); err != nil {
+			t.Errorf("validateDedupe(%q) = %v", mode, err)
+		}
+	}
+	if validateDedupe("team") == nil {
+		t.Error("accepted an unknown mode")
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// dumpFileName is the file a page is saved under by -dump-dir and
+// -fetch-only and read back from by -replay: the team name as a lowercase
+// slug, then a hash of the full page URL, query included, so a team's pages
+// in different currencies do not overwrite each other.
+func dumpFileName(team Team, pageURL string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(team.Name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug != "" {
+		slug += "-"
+	}
+	sum := sha256.Sum256([]byte(pageURL))
+	return fmt.Sprintf("%s%x.html", slug, sum[:6])
+}
+
+// loadPage returns the HTML of one of team's pages: read from the -replay
+// directory without any network activity, or fetched from pageURL (and
+// saved to -dump-dir when set). Pages are saved as the decoded UTF-8
+// text that was parsed. It also returns the attempts made.
+func (s *Scraper) loadPage(ctx context.Context, team Team, pageURL string, rows *rowParser) (string, int, error) {
+	if s.replayDir != "" {
+		data, err := os.ReadFile(filepath.Join(s.replayDir, dumpFileName(team, pageURL)))
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to read replay file: %w", err)
+		}
+		if contentBytes(data) > 0 {
+			s.stats.update(func(rs *RunStats) { rs.PagesWithBody++ })
+		}
+		// Saved pages are already UTF-8; decoding them again by their
+		// <meta charset> would mangle every non-ASCII character.
+		return string(data), 0, nil
+	}
+
+	html, _, attempts, err := s.fetchWithRetry(ctx, team, pageURL, rows)
+	if err != nil || s.dumpDir == "" {
+		return html, attempts, err
+	}
+
+	path := filepath.Join(s.dumpDir, dumpFileName(team, pageURL))
+	if err := os.WriteFile(path, []byte(html), 0644); err != nil {
+		log.Printf("Error saving %s: %v\n", path, err)
+		return html, attempts, nil
+	}
+	s.debugf("Saved %s to %s\n", team.Name, path)
+	return html, attempts, nil
+}
+
+// capturePage fetches a team's page for -fetch-only and saves it under the
+// same name -replay reads, with the response headers alongside in a
+// ".headers" file. It returns the attempts made.
+func (s *Scraper) capturePage(ctx context.Context, team Team, pageURL string) (int, error) {
+	html, header, attempts, err := s.fetchWithRetry(ctx, team, pageURL, nil)
+	if err != nil {
+		return attempts, err
+	}
+
+	path := filepath.Join(s.fetchOnly, dumpFileName(team, pageURL))
+	if err := os.WriteFile(path, []byte(html), 0644); err != nil {
+		return attempts, fmt.Errorf("failed to save page: %w", err)
+	}
+	if err := writeHeaders(strings.TrimSuffix(path, ".html")+".headers", savedHeaders(header)); err != nil {
+		return attempts, err
+	}
+	s.debugf("Saved %s to %s\n", team.Name, path)
+	return attempts, nil
+}
+
+// savedHeaders adjusts response headers to describe the page as saved:
+// decompressed and decoded to UTF-8, so the encoding and length of the
+// original response no longer apply.
+func savedHeaders(header http.Header) http.Header {
+	saved := header.Clone()
+	saved.Del("Content-Encoding")
+	saved.Del("Content-Length")
+	if mediaType, params, err := mime.ParseMediaType(saved.Get("Content-Type")); err == nil && params["charset"] != "" {
+		params["charset"] = "utf-8"
+		saved.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+	}
+	return saved
+}
+
+// writeHeaders saves response headers in HTTP wire format, one per line.
+func writeHeaders(path string, header http.Header) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to save headers: %w", err)
+	}
+	if err := header.Write(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to save headers: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to save headers: %w", err)
+	}
+	return nil
+}
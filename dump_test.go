@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestDumpFileNameKeysByURL(t *testing.T) {
+	team := Team{Name: "Bradford City"}
+	eur := dumpFileName(team, "https://example.com/team/1?currency=EUR")
+	gbp := dumpFileName(team, "https://example.com/team/1?currency=GBP")
+	if eur == gbp {
+		t.Errorf("both currencies are saved as %s", eur)
+	}
+	if again := dumpFileName(team, "https://example.com/team/1?currency=EUR"); again != eur {
+		t.Errorf("same URL named %s and %s", eur, again)
+	}
+	if got := dumpFileName(Team{Name: "?!"}, "https://example.com/"); got[0] == '-' {
+		t.Errorf("name without letters = %s", got)
+	}
+}
+
+// Two currency variants of one team page are dumped to separate files, and a
+// replay reads each back for its own currency.
+func TestDumpAndReplayCurrencies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		price := map[string]string{"EUR": "€1.2M", "GBP": "£1.0M"}[r.URL.Query().Get("currency")]
+		_, _ = w.Write([]byte(`<table><tr><td>John Smith</td><td>60</td><td>80</td><td>20</td><td>18</td><td>` + price + `</td></tr></table>`))
+	}))
+	defer srv.Close()
+	team := Team{Name: "A", URL: srv.URL + "/team/a"}
+	dir := t.TempDir()
+
+	dumper := newTestScraper(t)
+	dumper.dumpDir = dir
+	dumper.currencies = []string{"EUR", "GBP"}
+	if _, status := dumper.processTeam(context.Background(), team); status.Error != "" {
+		t.Fatal(status.Error)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("dumped %d files, want one per currency", len(files))
+	}
+
+	srv.Close() // A replay makes no requests.
+	replayer := newTestScraper(t)
+	replayer.replayDir = dir
+	replayer.currencies = []string{"EUR", "GBP"}
+	players, status := replayer.processTeam(context.Background(), team)
+	if status.Error != "" {
+		t.Fatal(status.Error)
+	}
+	if len(players) != 1 {
+		t.Fatalf("replayed %d players, want 1", len(players))
+	}
+	if p := players[0].Prices; p["EUR"] != 1200000 || p["GBP"] != 1000000 {
+		t.Errorf("replayed prices %v, want EUR 1200000 and GBP 1000000", p)
+	}
+}
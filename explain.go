@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// teamsFromStream reports whether the team list is only known by fetching
+// the -league-url page or reading stdin, which -explain does not do.
+func (s *Scraper) teamsFromStream() bool {
+	return s.leagueURL != "" || s.teamsFrom == "-"
+}
+
+// explain prints the fully-resolved configuration for a run over teamList.
+// When teamsFromStream, the list is not resolved and teamList is ignored.
+// Credentials are redacted.
+func (s *Scraper) explain(w io.Writer, teamList []Team) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(name string, value any) {
+		_, _ = fmt.Fprintf(tw, "%s\t%v\n", name, value)
+	}
+
+	row("min potential", s.minPotential)
+	row("min growth", s.minGrowth)
+	row("min price", boundString(s.minPrice))
+	row("max price", boundString(s.maxPrice))
+	row("min value ratio", fmt.Sprintf("%v (free players: %s)", s.minValueRatio, s.freeValue))
+	if len(s.currencies) > 0 {
+		row("currencies", fmt.Sprintf("%s (via ?%s=)", strings.Join(s.currencies, ", "), s.currencyParam))
+	}
+	if s.contractBefore > 0 {
+		row("contract before", s.contractBefore)
+	}
+	if s.minHeight > 0 {
+		row("min height", s.minHeight)
+	}
+	if len(s.rates) > 0 {
+		row("rates", fmt.Sprintf("%s (base %s)", s.rates.String(), s.baseCurrency))
+	}
+	if s.explainPlayers {
+		row("explain players", s.explainPlayers)
+	}
+	row("on inconsistent", s.onInconsistent)
+	row("growth source", fmt.Sprintf("%s (tolerance %d)", s.growthSource, s.growthTolerance))
+	row("dedupe", s.dedupe)
+	if len(s.require) > 0 {
+		row("require", fmt.Sprintf("%s (on missing: %s)", strings.Join(s.require, ", "), s.onMissing))
+	}
+	if len(s.redact) > 0 {
+		row("redact", strings.Join(s.redact, ", "))
+	}
+	if s.hashProfiles {
+		row("hash profiles", s.hashProfiles)
+	}
+	zero := "ignore"
+	switch {
+	case s.failZeroAfterFilter:
+		zero = "error"
+	case s.warnZeroAfterFilter:
+		zero = "warn"
+	}
+	row("zero after filter", zero)
+	if s.minPlayersPerTeam > 0 {
+		row("min players per team", s.minPlayersPerTeam)
+	}
+	sortKey := s.sortKey
+	if sortKey == "" {
+		sortKey = "none"
+	}
+	row("sort", sortKey)
+	row("leaderboard", s.leaderboard)
+	row("columns", s.columns.String())
+	row("min cells", s.requiredCells())
+	if s.render {
+		browser, err := s.browserPath()
+		if err != nil {
+			browser = err.Error()
+		} else if s.renderNoSandbox {
+			browser += " (sandbox disabled)"
+		}
+		row("render fallback", browser)
+	}
+	scheduling := "semaphore"
+	if s.partition {
+		scheduling = "partitioned"
+	}
+	concurrency := fmt.Sprintf("%d (%s)", s.concurrency, scheduling)
+	if workers := s.workers(len(teamList)); workers < s.concurrency && !s.teamsFromStream() {
+		concurrency = fmt.Sprintf("%d (%s; %d used for %d teams)", s.concurrency, scheduling, workers, len(teamList))
+	}
+	row("concurrency", concurrency)
+	row("ramp-up", durationString(s.rampUp))
+	row("dispatch order", s.dispatch)
+	row("ordered", s.ordered)
+	row("result buffer", s.resultBuffer)
+	row("delay", fmt.Sprintf("%v-%v", s.minDelay, s.maxDelay))
+	row("error backoff", s.errorBackoff)
+	row("request timeout", durationString(s.requestTimeout))
+	row("team timeout", durationString(s.teamTimeout))
+	row("total timeout", durationString(s.totalTimeout))
+	protocol := "auto (HTTP/2 when offered)"
+	if s.http1 {
+		protocol = "HTTP/1.1 only"
+	}
+	row("protocol", protocol)
+	if s.httpTrace {
+		row("http trace", s.httpTrace)
+	}
+	stream := "off"
+	if s.streamAbove > 0 {
+		stream = fmt.Sprintf("above %d bytes or unknown length", s.streamAbove)
+	}
+	row("streaming parse", stream)
+	row("retries", fmt.Sprintf("%d (backoff %v)", s.retries, s.retryBackoff))
+	row("user-agent policy", s.uaPolicy)
+	auth := "none"
+	if s.basicAuth.set {
+		auth = s.basicAuth.String() + " for " + strings.Join(s.basicAuthHosts, ", ")
+	}
+	row("basic auth", auth)
+	row("outputs", strings.Join(s.outputs, ", "))
+	if s.poll > 0 {
+		row("poll", s.poll)
+	}
+	if s.runRetries > 0 {
+		row("run retries", fmt.Sprintf("%d (delay %v)", s.runRetries, s.runRetryDelay))
+	}
+	row("only changed", s.onlyChanged)
+	if s.validateOutput {
+		row("validate output", s.validateOutput)
+	}
+	if s.newOnly != "" {
+		row("new only", fmt.Sprintf("%s (compared with %s)", s.newOnly, s.outputs[0]))
+	}
+	if s.flushInterval > 0 || s.flushEvery > 0 {
+		row("live flush", fmt.Sprintf("every %v / %d players", s.flushInterval, s.flushEvery))
+	}
+	if s.skipLogFile != "" {
+		row("skip log", s.skipLogFile)
+	}
+	if s.summaryFile != "" {
+		row("summary", s.summaryFile)
+	}
+	if s.summaryOnly {
+		row("summary only", s.summaryOnly)
+	}
+	if s.aggregatesFile != "" {
+		row("aggregates", s.aggregatesFile)
+	}
+	if s.teamSummaryFile != "" {
+		row("team summary", s.teamSummaryFile)
+	}
+
+	source := "built-in"
+	switch s.teamsFrom {
+	case "":
+		if s.leagueURL != "" {
+			source = "discovered on " + redactURL(s.leagueURL)
+		}
+	case "-":
+		source = "stdin"
+	default:
+		source = s.teamsFrom
+	}
+	if s.stateFile != "" {
+		row("state", fmt.Sprintf("%s (resume %v, fresh for %v, ttl %v)", s.stateFile, s.resume, s.freshFor, s.stateTTL))
+	}
+	if s.dumpDir != "" {
+		row("dump dir", s.dumpDir)
+	}
+	if s.replayDir != "" {
+		row("replay from", s.replayDir)
+	}
+	if s.fetchOnly != "" {
+		row("fetch only", s.fetchOnly)
+	}
+	row("team source", source)
+	if len(s.only) > 0 {
+		row("only", strings.Join(s.only, ", "))
+	}
+	if s.shuffle {
+		row("shuffle", s.shuffle)
+	}
+	if s.maxTeams > 0 {
+		row("max teams", s.maxTeams)
+	}
+	if s.teamsFromStream() {
+		row("teams", "resolved when the run starts")
+		return tw.Flush()
+	}
+	row("teams", len(teamList))
+	for _, t := range teamList {
+		value := redactURL(t.URL)
+		if t.MinDelay != nil || t.MaxDelay != nil {
+			minDelay, maxDelay := t.delays(s.minDelay, s.maxDelay)
+			value += fmt.Sprintf(" (delay %v-%v)", minDelay, maxDelay)
+		}
+		if len(t.Headers) > 0 {
+			// Header values may carry tokens, so only the names are shown.
+			names := make([]string, 0, len(t.Headers))
+			for name := range t.Headers {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			value += fmt.Sprintf(" (headers %s)", strings.Join(names, ", "))
+		}
+		row("  "+t.Name, value)
+	}
+
+	return tw.Flush()
+}
+
+// boundString formats an optional price bound.
+func boundString(b priceBound) string {
+	if !b.set {
+		return "none"
+	}
+	return b.String()
+}
+
+// durationString formats an optional time limit.
+func durationString(d time.Duration) string {
+	if d <= 0 {
+		return "none"
+	}
+	return d.String()
+}
+
+// redactURL hides any password embedded in a URL.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	return u.Redacted()
+}
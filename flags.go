@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"strings"
+)
+
+// listFlag is a flag.Value that accepts repeated and comma-separated values.
+// The first explicit value replaces the defaults instead of appending to them.
+type listFlag struct {
+	values *[]string
+	set    bool
+}
+
+func (l *listFlag) String() string {
+	if l.values == nil {
+		return ""
+	}
+	return strings.Join(*l.values, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	if !l.set {
+		*l.values = nil
+		l.set = true
+	}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l.values = append(*l.values, v)
+		}
+	}
+	return nil
+}
+
+// registerFlags binds the command-line flags to the scraper's settings.
+// Defaults come from the values already set by NewScraper.
+func (s *Scraper) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&s.teamsFrom, "teams", s.teamsFrom, "read the team list from this file instead of the built-in one (\"-\" for stdin); JSON or name,url lines")
+	fs.StringVar(&s.leagueURL, "league-url", s.leagueURL, "discover the team list from the /team/ links on this league page")
+	fs.Var(&listFlag{values: &s.only}, "only", "only scrape the teams with these names (comma-separated, case-insensitive)")
+	fs.BoolVar(&s.shuffle, "shuffle", s.shuffle, "scrape the teams in random order")
+	fs.IntVar(&s.maxTeams, "max-teams", s.maxTeams, "scrape at most this many teams, taken after -only and -shuffle (0 for no limit)")
+	fs.Var(&listFlag{values: &s.outputs}, "out", "output file; repeat or comma-separate for multiple destinations (format chosen by extension: .json, .csv, .parquet)")
+	fs.IntVar(&s.concurrency, "concurrency", s.concurrency, "number of teams fetched in parallel")
+	fs.IntVar(&s.resultBuffer, "result-buffer", s.resultBuffer, "number of players workers can hand over before waiting for the collector (0 for none)")
+	fs.DurationVar(&s.rampUp, "ramp-up", s.rampUp, "slow start: grow concurrency from 1 to -concurrency over this period (0 disables)")
+	fs.StringVar(&s.dispatch, "dispatch-order", s.dispatch, "order teams are fetched in: in-order (list order) or round-robin (interleaved across hosts)")
+	fs.BoolVar(&s.partition, "partition", s.partition, "split the team list into one contiguous chunk per worker, each processed in order (reproducible scheduling)")
+	fs.DurationVar(&s.poll, "poll", s.poll, "keep scraping: start a new run this long after each one finishes, until interrupted (0 runs once)")
+	fs.IntVar(&s.runRetries, "run-retries", s.runRetries, "run the whole scrape again up to this many times when no team succeeded, then exit non-zero")
+	fs.DurationVar(&s.runRetryDelay, "run-retry-delay", s.runRetryDelay, "wait this long before each -run-retries attempt")
+	fs.BoolVar(&s.onlyChanged, "only-changed", s.onlyChanged, "only rewrite an output when the results differ from the last run (hash kept in <output>.sha256)")
+	fs.BoolVar(&s.validateOutput, "validate-output", s.validateOutput, "read every output back after writing it and check it decodes to the same number of players")
+	fs.StringVar(&s.newOnly, "new-only", s.newOnly, "also write the players that were not in the previous results (the first -out file) to this file")
+	fs.DurationVar(&s.flushInterval, "flush-interval", s.flushInterval, "rewrite the outputs with the players found so far at this interval (0 disables)")
+	fs.IntVar(&s.flushEvery, "flush-every", s.flushEvery, "rewrite the outputs with the players found so far after every N new players (0 disables)")
+	fs.BoolVar(&s.ordered, "ordered", s.ordered, "write players grouped by team in the original team order")
+	fs.BoolVar(&s.http1, "http1", s.http1, "force HTTP/1.1 instead of negotiating HTTP/2")
+	fs.BoolVar(&s.httpTrace, "http-trace", s.httpTrace, "log DNS, connect, TLS handshake, first-byte and total times for every request")
+	fs.Int64Var(&s.streamAbove, "stream-above", s.streamAbove, "read responses larger than this many bytes (or of unknown length) row by row instead of buffering them whole; 0 disables it")
+	fs.IntVar(&s.retries, "retries", s.retries, "extra attempts per team after a network error, 429 or 5xx response (at most 10)")
+	fs.DurationVar(&s.retryBackoff, "retry-backoff", s.retryBackoff, "delay before the first retry; doubled for each further retry, up to 10m")
+	fs.Float64Var(&s.errorBackoff, "error-backoff-multiplier", s.errorBackoff, "multiply the delay between requests by this after each failed request, decaying after successes (1 disables)")
+	fs.StringVar(&s.sortKey, "sort", s.sortKey, "comma-separated sort keys applied in order, best first unless prefixed with - (descending) or + (ascending): potential, growth, overall, age, price, price_normalized, value_ratio")
+	fs.IntVar(&s.leaderboard, "leaderboard", s.leaderboard, "log a table of the top N players by the sort key (potential by default)")
+	fs.StringVar(&s.uaPolicy, "ua-policy", s.uaPolicy, "when to change the user agent: per-request, per-host or per-run")
+	fs.Float64Var(&s.minValueRatio, "min-value-ratio", s.minValueRatio, "skip players with less potential per million of price than this")
+	fs.StringVar(&s.freeValue, "free-value", s.freeValue, "how free players rank by value ratio: infinite (best possible) or exclude (no ratio)")
+	fs.IntVar(&s.contractBefore, "contract-before", s.contractBefore, "keep only players whose contract expires before this year (needs a contract column, see -columns)")
+	fs.Var(&listFlag{values: &s.redact}, "redact", "blank these fields in the outputs while still using them during the run (comma-separated: price, age, contract, height, weight)")
+	fs.BoolVar(&s.hashProfiles, "hash-profiles", s.hashProfiles, "replace player names with a short one-way hash (first 8 hex digits of SHA-256)")
+	fs.IntVar(&s.minHeight, "min-height", s.minHeight, "keep only players at least this tall in cm (needs a height column, see -columns)")
+	fs.BoolVar(&s.explainPlayers, "explain-players", s.explainPlayers, "attach to each kept player the filters it cleared and by how much")
+	fs.Var(&listFlag{values: &s.require}, "require", "drop players missing any of these fields (comma-separated, e.g. price,age)")
+	fs.StringVar(&s.onMissing, "on-missing", s.onMissing, "players missing a -require field: drop, or error to fail the run without writing outputs")
+	fs.StringVar(&s.dedupe, "dedupe", s.dedupe, "drop repeated players: per-team (one entry per player per team), global (one entry per player) or off")
+	fs.StringVar(&s.growthSource, "growth-source", s.growthSource, "where growth comes from: column (its own column) or computed (potential - overall)")
+	fs.IntVar(&s.growthTolerance, "growth-tolerance", s.growthTolerance, "log players whose growth column and potential - overall differ by more than this")
+	fs.StringVar(&s.onInconsistent, "on-inconsistent", s.onInconsistent, "rows whose potential is below overall: skip or clamp (raise potential to overall)")
+	fs.DurationVar(&s.requestTimeout, "request-timeout", s.requestTimeout, "maximum duration of a single HTTP request (0 for no limit)")
+	fs.DurationVar(&s.teamTimeout, "team-timeout", s.teamTimeout, "maximum duration of all work for one team (0 for no limit)")
+	fs.DurationVar(&s.totalTimeout, "timeout-total", s.totalTimeout, "maximum duration of the whole run (0 for no limit)")
+	fs.StringVar(&s.skipLogFile, "skip-log", s.skipLogFile, "write a JSONL record (team, reason, raw cells) for every skipped row to this file")
+	fs.StringVar(&s.stateFile, "state", s.stateFile, "track each source URL's last status in this JSON file")
+	fs.BoolVar(&s.resume, "resume", s.resume, "skip URLs in the -state file that succeeded within -fresh-for")
+	fs.DurationVar(&s.freshFor, "fresh-for", s.freshFor, "how long a successful scrape counts as fresh for -resume")
+	fs.DurationVar(&s.stateTTL, "state-ttl", s.stateTTL, "drop -state entries not scraped for this long")
+	fs.StringVar(&s.summaryFile, "summary-json", s.summaryFile, "write a JSON run summary (per-team attempts, player counts and errors) to this file")
+	fs.BoolVar(&s.summaryOnly, "summary-only", s.summaryOnly, "log aggregate figures (players per team, average potential, growth and age) instead of writing the player outputs")
+	fs.StringVar(&s.aggregatesFile, "aggregates-json", s.aggregatesFile, "write the aggregate figures of the players found as JSON to this file")
+	fs.StringVar(&s.teamSummaryFile, "team-summary", s.teamSummaryFile, "write one JSON record per team (prospects, average and best potential, total value) to this file")
+	fs.IntVar(&s.minPlayersPerTeam, "min-players-per-team", s.minPlayersPerTeam, "warn when a fetched team page has fewer than this many player rows before filtering (0 disables)")
+	fs.BoolVar(&s.warnZeroAfterFilter, "warn-zero-after-filter", s.warnZeroAfterFilter, "warn when player rows were found but none met the criteria")
+	fs.BoolVar(&s.failZeroAfterFilter, "fail-zero-after-filter", s.failZeroAfterFilter, "like -warn-zero-after-filter, but also exit with an error")
+	fs.Var(&s.columns, "columns", "override roster column indexes, e.g. price=6,age=5 (fields: profile, overall, potential, growth, age, price, contract, height, weight; -1 for absent)")
+	fs.IntVar(&s.minCells, "min-cells", s.minCells, "cells a row needs to count as a player row (default: highest mapped column + 1)")
+	fs.BoolVar(&s.render, "render", s.render, "when a page has no player rows, load it in a headless browser and parse the rendered DOM")
+	fs.StringVar(&s.chromePath, "chrome-path", s.chromePath, "browser executable for -render (default: search PATH for Chromium/Chrome)")
+	fs.BoolVar(&s.renderNoSandbox, "render-no-sandbox", s.renderNoSandbox, "run the -render browser with its sandbox disabled, for containers that cannot run it")
+	fs.StringVar(&s.dumpDir, "dump-dir", s.dumpDir, "save the HTML of each fetched team page in this directory")
+	fs.StringVar(&s.replayDir, "replay", s.replayDir, "parse the pages saved by -dump-dir in this directory instead of fetching")
+	fs.StringVar(&s.fetchOnly, "fetch-only", s.fetchOnly, "only download each team page and its response headers to this directory, without parsing or writing outputs")
+	fs.BoolVar(&s.debug, "debug", s.debug, "enable debug logging")
+	fs.BoolVar(&s.explainOnly, "explain", s.explainOnly, "print the effective configuration and exit without scraping")
+	fs.Var(&s.basicAuth, "basic-auth", "HTTP basic auth credentials as user:pass")
+	fs.Var(&listFlag{values: &s.basicAuthHosts}, "basic-auth-hosts", "hosts that receive the -basic-auth credentials (comma-separated; required with -basic-auth)")
+	fs.Var(&listFlag{values: &s.currencies}, "currencies", "fetch each team once per currency (e.g. GBP,EUR) and record every price; the first is used for filtering")
+	fs.StringVar(&s.currencyParam, "currency-param", s.currencyParam, "query parameter that selects the currency on team pages")
+	fs.Var(s.rates, "rates", "exchange rates relative to -base-currency, e.g. EUR=1.17,USD=1.27; fills price_normalized")
+	fs.StringVar(&s.baseCurrency, "base-currency", s.baseCurrency, "currency that -rates are relative to and price_normalized is expressed in")
+	fs.Var(&s.minPrice, "min-price", "skip players priced below this value (e.g. 500K, 1.5M)")
+	fs.Var(&s.maxPrice, "max-price", "skip players priced above this value (e.g. 500K, 1.5M)")
+}
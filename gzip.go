@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// responseBody returns a reader for resp's body with any gzip content
+// encoding removed, and the length of what it yields: resp.ContentLength,
+// or -1 for a compressed body, whose decompressed size is unknown until it
+// has been read. fetchHTML asks for gzip itself rather than leaving it to
+// the transport, because some servers declare gzip but send plain text,
+// which the transport would fail on. Such bodies are read as they are, with
+// a warning.
+func responseBody(resp *http.Response, url string) (io.Reader, int64, error) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp.Body, resp.ContentLength, nil
+	}
+
+	br := bufio.NewReader(resp.Body)
+	magic, _ := br.Peek(len(gzipMagic))
+	if !bytes.Equal(magic, gzipMagic) {
+		log.Printf("Warning: %s is declared as gzip but is not compressed; reading it as plain text\n", redactURL(url))
+		return br, resp.ContentLength, nil
+	}
+
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decompress gzip body: %w", err)
+	}
+	return zr, -1, nil
+}
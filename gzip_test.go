@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func gzipped(t *testing.T, text string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(text)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestResponseBody(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		encoding   string
+		body       []byte
+		wantLength int64
+	}{
+		{"plain", "", []byte(rosterPage), int64(len(rosterPage))},
+		{"gzip", "gzip", gzipped(t, rosterPage), -1},
+		{"gzip-declared plain text", "gzip", []byte(rosterPage), int64(len(rosterPage))},
+	} {
+		resp := &http.Response{
+			Header:        http.Header{},
+			Body:          io.NopCloser(bytes.NewReader(tc.body)),
+			ContentLength: int64(len(tc.body)),
+		}
+		if tc.encoding != "" {
+			resp.Header.Set("Content-Encoding", tc.encoding)
+		}
+
+		r, length, err := responseBody(resp, "http://example.com/team/a")
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if string(got) != rosterPage {
+			t.Errorf("%s: read %q", tc.name, got)
+		}
+		if length != tc.wantLength {
+			t.Errorf("%s: length = %d, want %d", tc.name, length, tc.wantLength)
+		}
+	}
+}
+
+// A server declaring gzip but sending plain text still yields its players.
+func TestFetchGzipDeclaredPlainText(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = io.WriteString(w, rosterPage)
+	}))
+	defer srv.Close()
+
+	s := newTestScraper(t)
+	team := Team{Name: "A", URL: srv.URL}
+	html, _, err := s.fetchHTML(context.Background(), team, team.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if players, _ := s.extractPlayers(team, html); len(players) != 2 {
+		t.Errorf("got %d players, want 2", len(players))
+	}
+}
+
+// A small compressed body can expand to a page above -stream-above, so
+// gzip pages are streamed whatever their Content-Length.
+func TestFetchGzipIsStreamed(t *testing.T) {
+	var page strings.Builder
+	page.WriteString("<html><table>\n")
+	for i := range 2000 {
+		fmt.Fprintf(&page, "<tr><td>Player %d</td><td>60</td><td>80</td><td>20</td><td>18</td><td>€1.2M</td></tr>\n", i)
+	}
+	page.WriteString(strings.Repeat("<p>filler</p>\n", 2000) + "</table></html>\n")
+	body := gzipped(t, page.String())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	s := newTestScraper(t)
+	s.streamAbove = int64(len(body)) * 2
+	team := Team{Name: "A", URL: srv.URL}
+	rows := s.newRowParser(team)
+	html, _, err := s.fetchHTML(context.Background(), team, team.URL, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if html != "" || !rows.fed {
+		t.Error("gzip page was buffered whole")
+	}
+	if players, _ := rows.result(); len(players) != 2000 {
+		t.Errorf("got %d players, want 2000", len(players))
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMain keeps the scraper's progress logging out of the test output.
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// rosterPage is a fixture team page in the default column layout.
+const rosterPage = `<html><table>
+<tr><th>Name</th><th>OVR</th><th>POT</th><th>Growth</th><th>Age</th><th>Value</th></tr>
+<tr><td><a href="/p/1">John Smith</a></td><td>60</td><td>80</td><td>20</td><td>18</td><td>€1.2M</td></tr>
+<tr><td><a href="/p/2">Old Guy</a></td><td>75</td><td>76</td><td>1</td><td>30</td><td>€5M</td></tr>
+<tr><td>Free Lad</td><td>50</td><td>72</td><td>22</td><td>16</td><td>Free</td></tr>
+</table></html>
+`
+
+// newTestScraper returns a scraper with no politeness delays that writes its
+// default output into a temporary directory.
+func newTestScraper(t testing.TB) *Scraper {
+	t.Helper()
+	s := NewScraper()
+	s.minDelay, s.maxDelay = 0, 0
+	s.retryBackoff = 0
+	s.outputs = []string{filepath.Join(t.TempDir(), "players.json")}
+	return s
+}
+
+// servePage starts a server answering every request with body.
+func servePage(t testing.TB, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// profiles lists the players' names in order.
+func profiles(players []Player) []string {
+	names := make([]string, len(players))
+	for i, p := range players {
+		names[i] = p.Profile
+	}
+	return names
+}
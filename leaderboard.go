@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"text/tabwriter"
+)
+
+// logLeaderboard logs a table of the top n players by the configured sort
+// keys (potential when none are set). It does not reorder players.
+func (s *Scraper) logLeaderboard(players []Player, n int) {
+	key := s.sortKey
+	if key == "" {
+		key = defaultSortKey
+	}
+
+	ranked := append([]Player(nil), players...)
+	sortPlayers(ranked, key)
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "#\tNAME\tTEAM\tOVR\tPOT\tGROWTH\tPRICE")
+	for i, p := range ranked {
+		name := p.Profile
+		if s.hashProfiles {
+			name = hashProfile(name)
+		}
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%d\t+%d\t%s\n", i+1, name, p.Team, p.Overall, p.Potential, p.Growth, p.Price)
+	}
+	_ = tw.Flush()
+
+	log.Printf("Top %d prospects by %s:\n%s", len(ranked), key, b.String())
+}
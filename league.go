@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// teamLinkPattern finds links to team pages on a league page.
+var teamLinkPattern = regexp.MustCompile(`(?is)<a\s[^>]*href\s*=\s*["']([^"']*/team/[^"']*)["'][^>]*>(.*?)</a>`)
+
+// discoverTeams fetches the -league-url page and returns the teams it links
+// to, in page order. Links are resolved against the page URL and
+// deduplicated, ignoring any fragment; a team is named by its link text, or
+// by the last part of its URL when the link has no text.
+func (s *Scraper) discoverTeams(ctx context.Context) ([]Team, error) {
+	s.configureTransport()
+	base, err := url.Parse(s.leagueURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid league URL: %w", err)
+	}
+
+	league := Team{Name: "league page", URL: s.leagueURL}
+	html, _, _, err := s.fetchWithRetry(ctx, league, s.leagueURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch league page: %w", err)
+	}
+
+	var list []Team
+	seen := make(map[string]bool)
+	for _, m := range teamLinkPattern.FindAllStringSubmatch(html, -1) {
+		ref, err := url.Parse(strings.TrimSpace(m[1]))
+		if err != nil {
+			s.debugf("Ignoring malformed team link %q\n", m[1])
+			continue
+		}
+		u := base.ResolveReference(ref)
+		u.Fragment = ""
+		if seen[u.String()] {
+			continue
+		}
+		seen[u.String()] = true
+
+		name := s.stripTags(m[2])
+		if name == "" {
+			name = path.Base(strings.TrimSuffix(u.Path, "/"))
+		}
+		t := Team{Name: name, URL: u.String()}
+		if err := t.check(); err != nil {
+			s.debugf("Ignoring team link: %v\n", err)
+			continue
+		}
+		list = append(list, t)
+	}
+	if len(list) == 0 {
+		return nil, errors.New("no team links found on the league page")
+	}
+	log.Printf("Discovered %d teams on %s\n", len(list), redactURL(s.leagueURL))
+	return list, nil
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -20,30 +22,30 @@ import (
 var (
 	// Team data remains as a package-level variable as it's static configuration.
 	teams = []Team{
-		{"Bradford City", "https://www.fifacm.com/25/team/1804/bradford-city"},
-		{"Doncaster Rovers", "https://www.fifacm.com/25/team/142/doncaster-rovers"},
-		{"Carlisle United", "https://www.fifacm.com/25/team/1480/carlisle-united"},
-		{"Swindon Town", "https://www.fifacm.com/25/team/1934/swindon-town"},
-		{"Chesterfield", "https://www.fifacm.com/25/team/1924/chesterfield"},
-		{"Tranmere Rovers", "https://www.fifacm.com/25/team/15048/tranmere-rovers"},
-		{"Crewe Alexandra", "https://www.fifacm.com/25/team/121/crewe-alexandra"},
-		{"Walsall", "https://www.fifacm.com/25/team/1803/walsall"},
-		{"Notts County", "https://www.fifacm.com/25/team/1937/notts-county"},
-		{"Port Vale", "https://www.fifacm.com/25/team/1928/port-vale"},
-		{"Grimsby Town", "https://www.fifacm.com/25/team/92/grimsby-town"},
-		{"Gillingham", "https://www.fifacm.com/25/team/1802/gillingham"},
-		{"Cheltenham Town", "https://www.fifacm.com/25/team/1936/cheltenham-town"},
-		{"Milton Keynes Dons", "https://www.fifacm.com/25/team/1798/milton-keynes-dons"},
-		{"AFC Wimbledon", "https://www.fifacm.com/25/team/112259/afc-wimbledon"},
-		{"Salford City", "https://www.fifacm.com/25/team/113926/salford-city"},
-		{"Newport County", "https://www.fifacm.com/25/team/112254/newport-county"},
-		{"Bromley", "https://www.fifacm.com/25/team/112764/bromley"},
-		{"Barrow", "https://www.fifacm.com/25/team/381/barrow"},
-		{"Harrogate Town", "https://www.fifacm.com/25/team/112222/harrogate-town"},
-		{"Fleetwood Town", "https://www.fifacm.com/25/team/112260/fleetwood-town"},
-		{"Morecambe", "https://www.fifacm.com/25/team/357/morecambe"},
-		{"Accrington Stanley", "https://www.fifacm.com/25/team/110313/accrington-stanley"},
-		{"Colchester United", "https://www.fifacm.com/25/team/1935/colchester-united"},
+		{Name: "Bradford City", URL: "https://www.fifacm.com/25/team/1804/bradford-city"},
+		{Name: "Doncaster Rovers", URL: "https://www.fifacm.com/25/team/142/doncaster-rovers"},
+		{Name: "Carlisle United", URL: "https://www.fifacm.com/25/team/1480/carlisle-united"},
+		{Name: "Swindon Town", URL: "https://www.fifacm.com/25/team/1934/swindon-town"},
+		{Name: "Chesterfield", URL: "https://www.fifacm.com/25/team/1924/chesterfield"},
+		{Name: "Tranmere Rovers", URL: "https://www.fifacm.com/25/team/15048/tranmere-rovers"},
+		{Name: "Crewe Alexandra", URL: "https://www.fifacm.com/25/team/121/crewe-alexandra"},
+		{Name: "Walsall", URL: "https://www.fifacm.com/25/team/1803/walsall"},
+		{Name: "Notts County", URL: "https://www.fifacm.com/25/team/1937/notts-county"},
+		{Name: "Port Vale", URL: "https://www.fifacm.com/25/team/1928/port-vale"},
+		{Name: "Grimsby Town", URL: "https://www.fifacm.com/25/team/92/grimsby-town"},
+		{Name: "Gillingham", URL: "https://www.fifacm.com/25/team/1802/gillingham"},
+		{Name: "Cheltenham Town", URL: "https://www.fifacm.com/25/team/1936/cheltenham-town"},
+		{Name: "Milton Keynes Dons", URL: "https://www.fifacm.com/25/team/1798/milton-keynes-dons"},
+		{Name: "AFC Wimbledon", URL: "https://www.fifacm.com/25/team/112259/afc-wimbledon"},
+		{Name: "Salford City", URL: "https://www.fifacm.com/25/team/113926/salford-city"},
+		{Name: "Newport County", URL: "https://www.fifacm.com/25/team/112254/newport-county"},
+		{Name: "Bromley", URL: "https://www.fifacm.com/25/team/112764/bromley"},
+		{Name: "Barrow", URL: "https://www.fifacm.com/25/team/381/barrow"},
+		{Name: "Harrogate Town", URL: "https://www.fifacm.com/25/team/112222/harrogate-town"},
+		{Name: "Fleetwood Town", URL: "https://www.fifacm.com/25/team/112260/fleetwood-town"},
+		{Name: "Morecambe", URL: "https://www.fifacm.com/25/team/357/morecambe"},
+		{Name: "Accrington Stanley", URL: "https://www.fifacm.com/25/team/110313/accrington-stanley"},
+		{Name: "Colchester United", URL: "https://www.fifacm.com/25/team/1935/colchester-united"},
 	}
 
 	userAgents = []string{
@@ -53,38 +55,155 @@ var (
 	}
 )
 
+// Pre-compiled parsing patterns. They are shared by every Scraper, since a
+// *regexp.Regexp is safe for concurrent use, so constructing one stays cheap.
+var (
+	cellPattern = regexp.MustCompile(`<td.*?>(.*?)</td>`)
+	tagStripper = regexp.MustCompile(`<.*?>`)
+)
+
 // --- Data Structures ---
 
-// Team holds the static information for a team.
+// Team holds the static information for a team. The optional delay and
+// header settings, available in JSON team lists, override the scraper's
+// defaults for requests to this team.
 type Team struct {
-	Name string
-	URL  string
+	Name     string            `json:"name"`
+	URL      string            `json:"url"`
+	MinDelay *Duration         `json:"min_delay,omitempty"`
+	MaxDelay *Duration         `json:"max_delay,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
 }
 
 // Player holds the scraped data for a player.
 type Player struct {
-	Profile   string `json:"profile"`
-	Team      string `json:"team"`
-	Price     string `json:"price"`
-	Age       int    `json:"age"`
-	Overall   int    `json:"overall"`
-	Potential int    `json:"potential"`
-	Growth    int    `json:"growth"`
+	Profile         string           `json:"profile"`
+	Team            string           `json:"team"`
+	Price           string           `json:"price"`
+	PriceValue      int64            `json:"price_value"`
+	Prices          map[string]int64 `json:"prices,omitempty"`           // Price per currency, with -currencies.
+	PriceNormalized int64            `json:"price_normalized,omitempty"` // PriceValue in the -base-currency, with -rates.
+	ValueRatio      float64          `json:"value_ratio"`                // Potential per million of price; 0 when free or unpriced.
+	Age             int              `json:"age"`
+	Overall         int              `json:"overall"`
+	Potential       int              `json:"potential"`
+	Growth          int              `json:"growth"`
+	ContractExpiry  string           `json:"contract_expiry,omitempty"` // As shown on the page, when a contract column is mapped.
+	ContractYear    int              `json:"contract_year,omitempty"`   // Year parsed from ContractExpiry.
+	Height          int              `json:"height,omitempty"`          // In cm, when a height column is mapped.
+	Weight          int              `json:"weight,omitempty"`          // In kg, when a weight column is mapped.
+	Reasons         []string         `json:"reasons,omitempty"`         // Filters cleared and by how much, with -explain-players.
+
+	valueRank float64 // ValueRatio, or the -free-value policy's stand-in; used for filtering and sorting.
+}
+
+// String formats the team as "Name <url>" for logging, hiding any credentials.
+func (t Team) String() string {
+	return fmt.Sprintf("%s <%s>", t.Name, redactURL(t.URL))
+}
+
+// String formats the player as "Name (Team) OVR/POT +growth, age N, price"
+// for concise logging.
+func (p Player) String() string {
+	return fmt.Sprintf("%s (%s) %d/%d +%d, age %d, %s", p.Profile, p.Team, p.Overall, p.Potential, p.Growth, p.Age, p.Price)
 }
 
 // Scraper encapsulates the state and methods for the scraping job.
 type Scraper struct {
-	client       *http.Client
-	minPotential int
-	minGrowth    int
-	outputFile   string
-	concurrency  int
-	minDelay     time.Duration
-	maxDelay     time.Duration
-	rand         *rand.Rand // Use a local rand instance to avoid global state.
-	rowPattern   *regexp.Regexp
-	cellPattern  *regexp.Regexp
-	tagStripper  *regexp.Regexp
+	// PostProcessor transforms the collected players before they are written.
+	// It runs once per Run, after every team has been collected, grouped and
+	// sorted, and its result is what the outputs receive. If it returns an error the
+	// unprocessed players are written instead.
+	PostProcessor func([]Player) ([]Player, error)
+
+	client              *http.Client
+	stats               *Stats        // Counters for the current run; replaced at the start of each Run.
+	requestTimeout      time.Duration // Bound on a single HTTP request, including the body read.
+	teamTimeout         time.Duration // Bound on all work for one team, including delays.
+	retries             int           // Extra attempts for a team after a retryable failure.
+	retryBackoff        time.Duration // Delay before the first retry; doubled for each one after.
+	runRetries          int           // Extra whole-run attempts when no team succeeded.
+	runRetryDelay       time.Duration // Delay before each extra whole-run attempt.
+	totalTimeout        time.Duration // Bound on the whole run.
+	poll                time.Duration // Start a new run this long after each one finishes; 0 runs once.
+	http1               bool          // Force HTTP/1.1 instead of negotiating HTTP/2.
+	httpTrace           bool          // Log connection timings for every request.
+	streamAbove         int64         // Parse larger (or unknown-length) responses row by row; 0 disables it.
+	minPotential        int
+	minGrowth           int
+	minPrice            priceBound
+	maxPrice            priceBound
+	minValueRatio       float64  // Minimum potential per million of price; 0 disables the filter.
+	freeValue           string   // How free players rank by value ratio.
+	contractBefore      int      // Keep only contracts expiring before this year; 0 disables the filter.
+	minHeight           int      // Keep only players at least this tall, in cm; 0 disables the filter.
+	explainPlayers      bool     // Attach the reasons each player was kept.
+	hashProfiles        bool     // Replace player names with a one-way hash.
+	redact              []string // Fields blanked in the outputs.
+	currencies          []string // Currencies to fetch each team in; the first is primary.
+	currencyParam       string
+	rates               rateTable // Exchange rates relative to baseCurrency for PriceNormalized.
+	baseCurrency        string
+	onInconsistent      string   // What to do with rows whose potential is below overall.
+	growthSource        string   // Where growth comes from: column or computed.
+	growthTolerance     int      // Largest tolerated gap between the growth column and potential - overall.
+	dedupe              string   // Which repeated players to drop: per-team, global or off.
+	require             []string // Fields every kept player must have.
+	onMissing           string   // What to do with players lacking a -require field.
+	teamsFrom           string   // Path of a team list to use instead of the built-in one; "-" for stdin.
+	leagueURL           string   // League page to discover the team list from.
+	only                []string // Team names to scrape; empty means all.
+	shuffle             bool     // Scrape teams in random order.
+	maxTeams            int      // Scrape at most this many teams; 0 means no limit.
+	outputs             []string
+	flushInterval       time.Duration // Rewrite outputs with partial results this often; 0 disables it.
+	flushEvery          int           // Rewrite outputs after this many new players; 0 disables it.
+	onlyChanged         bool          // Skip outputs whose result set has not changed since the last write.
+	validateOutput      bool          // Read each output back after writing it and check the player count.
+	newOnly             string        // Also write the players not in the previous results to this file.
+	summaryFile         string        // Where to write the run summary JSON; empty to skip.
+	summaryOnly         bool          // Log aggregate figures instead of writing the player outputs.
+	aggregatesFile      string        // Write the aggregate figures as JSON to this file.
+	teamSummaryFile     string        // Write one aggregate record per team as JSON to this file.
+	skipLogFile         string        // JSONL audit of discarded rows; empty disables it.
+	skipLog             *skipLog
+	stateFile           string // Per-URL status store; empty disables tracking.
+	resume              bool   // Skip URLs that succeeded within freshFor.
+	freshFor            time.Duration
+	stateTTL            time.Duration // Forget state entries older than this.
+	warnZeroAfterFilter bool          // Warn when rows were found but none passed the filters.
+	failZeroAfterFilter bool          // Fail the run in the same situation.
+	minPlayersPerTeam   int           // Warn about teams with fewer player rows than this; 0 disables it.
+	explainOnly         bool          // Print the resolved configuration and exit without scraping.
+	concurrency         int
+	resultBuffer        int           // Players workers can hand to the collector without waiting.
+	partition           bool          // Assign contiguous chunks of teams to fixed workers.
+	dispatch            string        // Order teams are fetched in: in-order or round-robin across hosts.
+	rampUp              time.Duration // Grow the effective concurrency from 1 to its full value over this period.
+	ordered             bool          // Group output by team in list order.
+	sortKey             string        // Order of the output; empty keeps collection order.
+	leaderboard         int           // Number of top players to log after the run; 0 disables it.
+	minDelay            time.Duration
+	maxDelay            time.Duration
+	errorBackoff        float64 // Factor applied to delays after each failed request; 1 disables it.
+	penaltyMu           sync.Mutex
+	penalty             float64 // Current delay multiplier, between 1 and maxPenalty.
+	randMu              sync.Mutex
+	rand                *rand.Rand // Use a local rand instance to avoid global state; guarded by randMu, as workers share it.
+	uaPolicy            string
+	basicAuth           basicAuth
+	basicAuthHosts      []string // Hosts that receive basicAuth; required with it.
+	uaMu                sync.Mutex
+	stickyAgents        map[string]string // User agents pinned by the per-host and per-run policies.
+	columns             columnMap
+	minCells            int  // Overrides the row width derived from columns when positive.
+	render              bool // Fall back to a headless browser when a page has no player rows.
+	chromePath          string
+	renderNoSandbox     bool   // Run the -render browser without its sandbox.
+	dumpDir             string // Save each fetched team page here.
+	fetchOnly           string // Only save each team's page and headers to this directory; no parsing or output.
+	replayDir           string // Parse pages saved by dumpDir instead of fetching.
+	debug               bool
 }
 
 // NewScraper creates and configures a new Scraper instance.
@@ -92,189 +211,1087 @@ func NewScraper() *Scraper {
 	source := rand.NewSource(time.Now().UnixNano())
 
 	return &Scraper{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
+		PostProcessor: func(players []Player) ([]Player, error) {
+			return players, nil
 		},
-		minPotential: 70,
-		minGrowth:    12,
-		outputFile:   "high_potential_players.json", // Outputting valid JSON now
-		concurrency:  3,
-		minDelay:     2 * time.Second,
-		maxDelay:     5 * time.Second,
-		rand:         rand.New(source),
-		rowPattern:   regexp.MustCompile(`<tr.*?>.*?</tr>`),
-		cellPattern:  regexp.MustCompile(`<td.*?>(.*?)</td>`),
-		tagStripper:  regexp.MustCompile(`<.*?>`),
+		// Timeouts are applied through contexts so the per-request, per-team
+		// and total bounds nest; the tightest deadline always wins.
+		client:          &http.Client{},
+		stats:           &Stats{},
+		requestTimeout:  30 * time.Second,
+		retryBackoff:    5 * time.Second,
+		runRetryDelay:   5 * time.Minute,
+		minPotential:    70,
+		minGrowth:       12,
+		onInconsistent:  inconsistentSkip,
+		dedupe:          dedupePerTeam,
+		onMissing:       missingDrop,
+		dispatch:        dispatchInOrder,
+		growthSource:    growthColumn,
+		growthTolerance: 1,
+		outputs:         []string{"high_potential_players.json"},
+		streamAbove:     4 << 20,
+		concurrency:     3,
+		resultBuffer:    64,
+		minDelay:        2 * time.Second,
+		maxDelay:        5 * time.Second,
+		rand:            rand.New(source),
+		errorBackoff:    1,
+		penalty:         1,
+		currencyParam:   "currency",
+		freeValue:       freeInfinite,
+		rates:           rateTable{},
+		baseCurrency:    "GBP",
+		freshFor:        24 * time.Hour,
+		stateTTL:        30 * 24 * time.Hour,
+		uaPolicy:        uaPerRequest,
+		stickyAgents:    make(map[string]string),
+		columns:         defaultColumns,
 	}
 }
 
-// fetchHTML fetches the HTML content from a given URL.
-func (s *Scraper) fetchHTML(url string) (string, error) {
-	// Random delay to avoid triggering rate limits.
-	delay := s.minDelay + time.Duration(s.rand.Int63n(int64(s.maxDelay-s.minDelay)))
-	time.Sleep(delay)
+// Exit codes used by main.
+const (
+	exitFailure       = 1 // Configuration errors and failed runs.
+	exitLayoutChanged = 3 // Pages were fetched but no player rows were found.
+)
+
+// errLayoutChanged reports a run where every page had content but no page
+// had a recognizable player row, which signals a parser/selector break
+// rather than a legitimately empty result.
+var errLayoutChanged = errors.New("no player rows found in any fetched page")
+
+// Sources accepted by -growth-source.
+const (
+	growthColumn   = "column"   // Read growth from its own column.
+	growthComputed = "computed" // Derive growth as potential - overall.
+)
+
+// Policies accepted by -on-inconsistent.
+const (
+	inconsistentSkip  = "skip"
+	inconsistentClamp = "clamp"
+)
+
+// validate checks the settings for values that cannot be used.
+func (s *Scraper) validate() error {
+	if s.errorBackoff < 1 {
+		return fmt.Errorf("-error-backoff-multiplier must be at least 1")
+	}
+	if s.resume && s.stateFile == "" {
+		return fmt.Errorf("-resume needs a -state file")
+	}
+	if s.minCells < 0 {
+		return fmt.Errorf("-min-cells must not be negative")
+	}
+	if s.flushInterval > 0 || s.flushEvery > 0 {
+		// Each of these must leave the outputs unwritten, or holding only
+		// the final result.
+		switch {
+		case s.ordered:
+			return fmt.Errorf("live flushing is not available with -ordered")
+		case s.summaryOnly:
+			return fmt.Errorf("live flushing is not available with -summary-only")
+		case len(s.require) > 0 && s.onMissing == missingError:
+			return fmt.Errorf("live flushing is not available with -on-missing=%s", missingError)
+		}
+	}
+	if s.concurrency < 1 {
+		return fmt.Errorf("-concurrency must be at least 1")
+	}
+	if s.streamAbove < 0 {
+		return fmt.Errorf("-stream-above must not be negative")
+	}
+	if s.leagueURL != "" && s.teamsFrom != "" {
+		return fmt.Errorf("-league-url and -teams cannot be combined")
+	}
+	if s.renderNoSandbox && !s.render {
+		return fmt.Errorf("-render-no-sandbox needs -render")
+	}
+	if s.basicAuth.set && len(s.basicAuthHosts) == 0 {
+		return fmt.Errorf("-basic-auth needs -basic-auth-hosts to name the hosts that receive the credentials")
+	}
+	if s.minPlayersPerTeam < 0 {
+		return fmt.Errorf("-min-players-per-team must not be negative")
+	}
+	if s.poll < 0 {
+		return fmt.Errorf("-poll must not be negative")
+	}
+	if s.runRetries < 0 {
+		return fmt.Errorf("-run-retries must not be negative")
+	}
+	if s.runRetryDelay < 0 {
+		return fmt.Errorf("-run-retry-delay must not be negative")
+	}
+	if s.runRetries > 0 && s.poll > 0 {
+		return fmt.Errorf("-run-retries cannot be combined with -poll")
+	}
+	if s.resultBuffer < 0 {
+		return fmt.Errorf("-result-buffer must not be negative")
+	}
+	switch s.dispatch {
+	case dispatchInOrder, dispatchRoundRobin:
+	default:
+		return fmt.Errorf("unknown -dispatch-order %q (want %s or %s)", s.dispatch, dispatchInOrder, dispatchRoundRobin)
+	}
+	if s.maxTeams < 0 {
+		return fmt.Errorf("-max-teams must not be negative")
+	}
+	for _, path := range s.outputs {
+		if _, err := formatFor(path); err != nil {
+			return fmt.Errorf("-out %s: %w", path, err)
+		}
+	}
+	if s.newOnly != "" {
+		if len(s.outputs) == 0 {
+			return fmt.Errorf("-new-only needs an -out file to compare against")
+		}
+		if _, err := formatFor(s.newOnly); err != nil {
+			return fmt.Errorf("-new-only: %w", err)
+		}
+	}
+	if s.fetchOnly != "" && (s.replayDir != "" || s.dumpDir != "") {
+		return fmt.Errorf("-fetch-only cannot be combined with -replay or -dump-dir")
+	}
+	if s.replayDir != "" && s.dumpDir != "" {
+		return fmt.Errorf("-replay and -dump-dir cannot be combined")
+	}
+	if s.contractBefore > 0 && s.columns.Contract < 0 {
+		return fmt.Errorf("-contract-before needs a contract column (e.g. -columns=contract=6)")
+	}
+	if s.minHeight > 0 && s.columns.Height < 0 {
+		return fmt.Errorf("-min-height needs a height column (e.g. -columns=height=7)")
+	}
+	if s.retries < 0 || s.retries > maxRetries {
+		return fmt.Errorf("-retries must be between 0 and %d", maxRetries)
+	}
+	if s.retryBackoff < 0 {
+		return fmt.Errorf("-retry-backoff must not be negative")
+	}
+	if s.sortKey != "" {
+		if _, err := parseSort(s.sortKey); err != nil {
+			return err
+		}
+	}
+	if s.freeValue != freeInfinite && s.freeValue != freeExclude {
+		return fmt.Errorf("unknown -free-value policy %q (want %s or %s)", s.freeValue, freeInfinite, freeExclude)
+	}
+	switch s.onInconsistent {
+	case inconsistentSkip, inconsistentClamp:
+	default:
+		return fmt.Errorf("unknown -on-inconsistent policy %q (want %s or %s)", s.onInconsistent, inconsistentSkip, inconsistentClamp)
+	}
+	if err := validateDedupe(s.dedupe); err != nil {
+		return err
+	}
+	if err := s.validateRequire(); err != nil {
+		return err
+	}
+	if err := s.validateRedact(); err != nil {
+		return err
+	}
+	switch s.growthSource {
+	case growthColumn, growthComputed:
+	default:
+		return fmt.Errorf("unknown -growth-source %q (want %s or %s)", s.growthSource, growthColumn, growthComputed)
+	}
+	if s.growthTolerance < 0 {
+		return fmt.Errorf("-growth-tolerance must not be negative")
+	}
+	return validateUAPolicy(s.uaPolicy)
+}
+
+// politeDelay waits a random delay between the team's bounds, stretched by
+// the error penalty, before a request to avoid triggering rate limits.
+func (s *Scraper) politeDelay(ctx context.Context, team Team) error {
+	minDelay, maxDelay := team.delays(s.minDelay, s.maxDelay)
+	delay := minDelay
+	if maxDelay > minDelay {
+		s.withRand(func(r *rand.Rand) {
+			delay += time.Duration(r.Int63n(int64(maxDelay - minDelay)))
+		})
+	}
+	return sleepContext(ctx, s.penalized(delay))
+}
+
+// recordRequest updates the error penalty and the request stats once a
+// request made under parent is over; started is zero when it was never
+// sent. Cancellation by the caller says nothing about the site, so it is
+// not counted; a request that hits its own timeout is.
+func (s *Scraper) recordRequest(parent context.Context, started time.Time, err error) {
+	if parent.Err() == nil {
+		s.recordOutcome(err != nil)
+	}
+	if started.IsZero() {
+		return
+	}
+	elapsed := time.Since(started)
+	s.stats.update(func(rs *RunStats) {
+		rs.Requests++
+		rs.FetchTime += elapsed
+		if err != nil {
+			rs.FailedRequests++
+		}
+	})
+}
+
+// fetchHTML fetches the HTML content from a given URL on behalf of team,
+// along with the response headers. When rows is set and the body is large
+// or of unknown length (see streamed), the page is instead handed to rows a
+// row at a time while it is read, and the returned HTML is empty.
+func (s *Scraper) fetchHTML(ctx context.Context, team Team, url string, rows *rowParser) (html string, header http.Header, err error) {
+	var started time.Time
+	defer func(parent context.Context) {
+		s.recordRequest(parent, started, err)
+	}(ctx)
+
+	if err := s.politeDelay(ctx, team); err != nil {
+		return "", nil, err
+	}
+
+	if s.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.requestTimeout)
+		defer cancel()
+	}
+
+	ctx, traced := s.traceRequest(ctx, url)
+	defer traced()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", userAgents[s.rand.Intn(len(userAgents))])
+	req.Header.Set("User-Agent", s.userAgentFor(url))
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
+	req.Header.Set("Accept-Encoding", "gzip")
+	for name, value := range team.Headers {
+		req.Header.Set(name, value)
+	}
+	s.applyBasicAuth(req)
 
+	started = time.Now()
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("HTTP request failed: %w", err)
+		return "", nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(resp.Body)
 
+	s.debugf("%s %s via %s\n", resp.Status, redactURL(url), resp.Proto)
+
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP request failed with status: %s", resp.Status)
+		return "", nil, &statusError{code: resp.StatusCode, status: resp.Status}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	reader, length, err := responseBody(resp, url)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if rows != nil {
+		rows.reset()
+	}
+	var body []byte
+	var content int64
+	if rows != nil && s.streamed(length) {
+		content, err = streamRows(reader, resp.Header.Get("Content-Type"), rows)
+		s.debugf("Streamed %s, %d player %s\n", redactURL(url), rows.rows, plural(rows.rows, "row"))
+	} else {
+		body, err = io.ReadAll(reader)
+		content = contentBytes(body)
+	}
 	if err != nil {
-		return "", fmt.Errorf("reading response body failed: %w", err)
+		return "", nil, fmt.Errorf("reading response body failed: %w", err)
+	}
+	// Counted here rather than from the returned HTML, which is empty for
+	// a streamed page.
+	if content > 0 {
+		s.stats.update(func(rs *RunStats) { rs.PagesWithBody++ })
+	}
+	if rows != nil && rows.fed {
+		return "", resp.Header, nil
 	}
 
-	return string(body), nil
+	return s.decodeBody(body, resp.Header.Get("Content-Type")), resp.Header, nil
 }
 
-// extractPlayers parses the HTML to find players matching the criteria.
-func (s *Scraper) extractPlayers(team Team, html string) []Player {
-	var players []Player
-	rows := s.rowPattern.FindAllString(html, -1)
+// extractPlayers parses the HTML to find players matching the criteria. It
+// also returns the number of player rows seen before any filtering.
+func (s *Scraper) extractPlayers(team Team, html string) ([]Player, int) {
+	rows := s.newRowParser(team)
+	_, _ = eachRow(strings.NewReader(html), rows.add) // Reading a string cannot fail.
+	return rows.result()
+}
 
-	for _, row := range rows {
-		cols := s.cellPattern.FindAllStringSubmatch(row, -1)
-		if len(cols) < 6 {
-			continue
+// safeParseRow is parseRow with a recover, so one malformed row cannot
+// abort the extraction of a whole team. A row that panics is skipped,
+// counted as salvaged rather than seen, and the next row is parsed.
+func (s *Scraper) safeParseRow(team Team, row string, kept map[string]bool) (p Player, isPlayer, keep bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Skipping a row for %s after a panic while parsing it: %v\n", team.Name, r)
+			s.debugf("Row that panicked: %s\n", row)
+			s.stats.update(func(rs *RunStats) { rs.SalvagedRows++ })
+			p, keep = Player{}, false
 		}
+	}()
+	return s.parseRow(team, row, kept)
+}
 
-		profile := s.stripTags(cols[0][1])
-		if strings.Contains(profile, "Loan") {
-			continue
-		}
+// parseRow parses one table row. It reports whether the row is a player row
+// at all and whether the player passed the filters; kept holds the rows
+// already kept for this team.
+func (s *Scraper) parseRow(team Team, row string, kept map[string]bool) (Player, bool, bool) {
+	cols := cellPattern.FindAllStringSubmatch(row, -1)
+	if len(cols) < s.requiredCells() {
+		s.logSkip(team, skipShortRow, cols)
+		return Player{}, false, false
+	}
 
-		potential, err := strconv.Atoi(s.stripTags(cols[2][1]))
-		if err != nil || potential < s.minPotential {
-			continue
-		}
+	profile := s.cell(cols, "profile", s.columns.Profile)
+	if strings.Contains(profile, "Loan") {
+		s.logSkip(team, skipLoan, cols)
+		return Player{}, true, false
+	}
 
-		growth, err := strconv.Atoi(s.stripTags(cols[3][1]))
-		if err != nil || growth < s.minGrowth {
-			continue
+	potential, err := strconv.Atoi(s.cell(cols, "potential", s.columns.Potential))
+	if err != nil {
+		s.stats.update(func(rs *RunStats) { rs.ParseFailures++ })
+		s.logSkip(team, skipParseFailure, cols)
+		return Player{}, true, false
+	}
+
+	// A potential below the current overall means the row was misparsed.
+	overall, _ := strconv.Atoi(s.cell(cols, "overall", s.columns.Overall))
+	if potential < overall {
+		if s.onInconsistent != inconsistentClamp {
+			log.Printf("Skipping %s (%s): potential %d is below overall %d\n", profile, team.Name, potential, overall)
+			s.logSkip(team, skipInconsistent, cols)
+			return Player{}, true, false
 		}
+		log.Printf("Clamping %s (%s): potential %d raised to overall %d\n", profile, team.Name, potential, overall)
+		potential = overall
+	}
+	if potential < s.minPotential {
+		s.logSkip(team, skipLowPotential, cols)
+		return Player{}, true, false
+	}
+
+	growth, err := s.growth(team, profile, cols, overall, potential)
+	if err != nil {
+		s.stats.update(func(rs *RunStats) { rs.ParseFailures++ })
+		s.logSkip(team, skipParseFailure, cols)
+		return Player{}, true, false
+	}
+	if growth < s.minGrowth {
+		s.logSkip(team, skipLowGrowth, cols)
+		return Player{}, true, false
+	}
 
-		overall, _ := strconv.Atoi(s.stripTags(cols[1][1]))
-		age, _ := strconv.Atoi(s.stripTags(cols[4][1]))
-		price := s.stripTags(cols[5][1])
+	age, _ := strconv.Atoi(s.cell(cols, "age", s.columns.Age))
+	price := s.cell(cols, "price", s.columns.Price)
+	priceValue, priced := parsePrice(price)
+	if !s.priceInRange(priceValue, priced) {
+		s.logSkip(team, skipPrice, cols)
+		return Player{}, true, false
+	}
 
-		players = append(players, Player{
-			Profile:   profile,
-			Team:      team.Name,
-			Price:     price,
-			Age:       age,
-			Overall:   overall,
-			Potential: potential,
-			Growth:    growth,
-		})
+	contract := s.optionalCell(cols, "contract", s.columns.Contract)
+	contractYear := parseContractYear(contract)
+	if s.contractBefore > 0 && (contractYear == 0 || contractYear >= s.contractBefore) {
+		s.logSkip(team, skipContract, cols)
+		return Player{}, true, false
+	}
+
+	height := parseMeasure(s.optionalCell(cols, "height", s.columns.Height))
+	if s.minHeight > 0 && height < s.minHeight {
+		s.logSkip(team, skipHeight, cols)
+		return Player{}, true, false
+	}
+	weight := parseMeasure(s.optionalCell(cols, "weight", s.columns.Weight))
+
+	ratio, rank := s.valueRatio(potential, priceValue, priced)
+	if s.minValueRatio > 0 && rank < s.minValueRatio {
+		s.logSkip(team, skipValueRatio, cols)
+		return Player{}, true, false
+	}
+
+	key := fmt.Sprintf("%s\x00%d\x00%d", profileKey(profile), overall, potential)
+	if kept[key] {
+		s.debugf("Skipping repeated row for %s (%s)\n", profile, team.Name)
+		s.logSkip(team, skipDuplicate, cols)
+		return Player{}, true, false
+	}
+	kept[key] = true
+
+	p := Player{
+		Profile:        profile,
+		Team:           team.Name,
+		Price:          price,
+		PriceValue:     priceValue,
+		ValueRatio:     ratio,
+		valueRank:      rank,
+		Age:            age,
+		Overall:        overall,
+		Potential:      potential,
+		Growth:         growth,
+		ContractExpiry: contract,
+		ContractYear:   contractYear,
+		Height:         height,
+		Weight:         weight,
+	}
+	if s.explainPlayers {
+		p.Reasons = s.keepReasons(p)
+	}
+	s.debugf("Kept %v\n", p)
+	return p, true, true
+}
+
+// growth returns a player's growth from the configured source. The growth
+// column and potential - overall should agree; a difference above
+// -growth-tolerance is logged, as it usually means the columns are
+// misaligned. An error means the growth column is needed but unreadable.
+func (s *Scraper) growth(team Team, profile string, cols [][]string, overall, potential int) (int, error) {
+	computed := potential - overall
+	column, err := strconv.Atoi(s.cell(cols, "growth", s.columns.Growth))
+	if err == nil && abs(column-computed) > s.growthTolerance {
+		log.Printf("Growth mismatch for %s (%s): column says %d, potential - overall is %d; the columns may be misaligned\n", profile, team.Name, column, computed)
+	}
+	if s.growthSource == growthComputed {
+		return computed, nil
+	}
+	return column, err
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// withRand calls fn with the scraper's random source while holding its
+// lock. A *rand.Rand is not safe for concurrent use.
+func (s *Scraper) withRand(fn func(r *rand.Rand)) {
+	s.randMu.Lock()
+	defer s.randMu.Unlock()
+	fn(s.rand)
+}
+
+// debugf logs a message only when debug logging is enabled.
+func (s *Scraper) debugf(format string, args ...any) {
+	if s.debug {
+		log.Printf("DEBUG "+format, args...)
 	}
-	return players
 }
 
 // stripTags removes HTML tags from a string.
 func (s *Scraper) stripTags(input string) string {
-	return strings.TrimSpace(s.tagStripper.ReplaceAllString(input, ""))
+	return strings.TrimSpace(tagStripper.ReplaceAllString(input, ""))
 }
 
-// writePlayersToFile saves the list of players to a valid JSON file.
-func (s *Scraper) writePlayersToFile(players []Player) error {
-	// Marshal the entire slice into a valid JSON array format with indentation.
-	jsonData, err := json.MarshalIndent(players, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal players to JSON: %w", err)
+// writeOutputs saves the players to every configured destination. A failing
+// destination is reported but does not stop the remaining ones. With
+// -only-changed, destinations whose last written result set is identical
+// are left untouched.
+func (s *Scraper) writeOutputs(players []Player) error {
+	players = s.redacted(players)
+	var hash string
+	if s.onlyChanged {
+		var err error
+		if hash, err = resultHash(players); err != nil {
+			return fmt.Errorf("failed to hash results: %w", err)
+		}
+	}
+
+	var errs []error
+	for _, path := range s.outputs {
+		if hash != "" && unchanged(path, hash) {
+			log.Printf("No changes for %s, not rewriting it\n", path)
+			continue
+		}
+		if err := writePlayersToFile(path, players); err != nil {
+			log.Printf("Error writing to %s: %v\n", path, err)
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		if s.validateOutput {
+			if err := checkOutput(path, len(players)); err != nil {
+				log.Printf("Error validating %s: %v\n", path, err)
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+				continue
+			}
+		}
+		log.Printf("Results saved to %s\n", path)
+		if hash != "" {
+			if err := saveHash(path, hash); err != nil {
+				log.Printf("Error writing to %s: %v\n", hashPath(path), err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// flushOutputs writes the players collected so far to every output. Each
+// file is replaced atomically, so a watcher only ever sees complete data.
+func (s *Scraper) flushOutputs(players []Player) {
+	players = s.redacted(players)
+	for _, path := range s.outputs {
+		if err := writePlayersToFile(path, players); err != nil {
+			log.Printf("Error flushing to %s: %v\n", path, err)
+			continue
+		}
+		// The file no longer holds the result set its hash describes, so
+		// the final write must not be skipped as unchanged.
+		if err := forgetHash(path); err != nil {
+			log.Printf("Error removing %s: %v\n", hashPath(path), err)
+		}
 	}
+	s.debugf("Flushed %d players to outputs\n", len(players))
+}
 
-	// Use os.WriteFile for a simpler file writing operation.
-	return os.WriteFile(s.outputFile, jsonData, 0644)
+// workers returns the number of teams to fetch in parallel for a list of
+// n teams: -concurrency, but no more than there are teams.
+func (s *Scraper) workers(n int) int {
+	return max(1, min(s.concurrency, n))
+}
+
+// sleepContext pauses for d, returning early with the context's error if it is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // processTeam is the worker function for a single team.
-func (s *Scraper) processTeam(team Team, results chan<- Player) {
-	html, err := s.fetchHTML(team.URL)
+func (s *Scraper) processTeam(ctx context.Context, team Team) ([]Player, TeamStatus) {
+	status := TeamStatus{Team: team.Name, URL: redactURL(team.URL)}
+
+	if s.teamTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.teamTimeout)
+		defer cancel()
+	}
+
+	// With -currencies the first currency is the primary one: its page
+	// provides the players and is the one the price filters apply to.
+	pageURL := team.URL
+	if len(s.currencies) > 0 {
+		var err error
+		if pageURL, err = s.currencyURL(team.URL, s.currencies[0]); err != nil {
+			status.Error = err.Error()
+			s.stats.update(func(rs *RunStats) { rs.TeamsFailed++ })
+			return nil, status
+		}
+	}
+
+	if s.fetchOnly != "" {
+		attempts, err := s.capturePage(ctx, team, pageURL)
+		status.Attempts = attempts
+		if err != nil {
+			log.Printf("Error fetching %s: %v\n", team.Name, err)
+			status.Error = err.Error()
+			s.stats.update(func(rs *RunStats) { rs.TeamsFailed++ })
+			return nil, status
+		}
+		s.stats.update(func(rs *RunStats) { rs.TeamsSucceeded++ })
+		return nil, status
+	}
+
+	s.debugf("Fetching %v\n", team)
+	parser := s.newRowParser(team)
+	html, attempts, err := s.loadPage(ctx, team, pageURL, parser)
+	status.Attempts = attempts
 	if err != nil {
 		log.Printf("Error fetching %s: %v\n", team.Name, err)
-		return
+		status.Error = err.Error()
+		s.stats.update(func(rs *RunStats) { rs.TeamsFailed++ })
+		return nil, status
 	}
 
-	players := s.extractPlayers(team, html)
-	for _, p := range players {
-		results <- p
+	// A streamed page was parsed while it was read.
+	var players []Player
+	var rows int
+	if parser.fed {
+		players, rows = parser.result()
+	} else {
+		players, rows = s.extractPlayers(team, html)
+	}
+	if rows == 0 && s.render && s.replayDir == "" {
+		// The table may be built by JavaScript; try the rendered DOM instead.
+		if rendered, err := s.renderHTML(ctx, team, pageURL); err != nil {
+			log.Printf("Rendering %s failed, keeping the static result: %v\n", team.Name, err)
+		} else {
+			s.debugf("No rows in static HTML for %s, parsed the rendered page instead\n", team.Name)
+			players, rows = s.extractPlayers(team, rendered)
+		}
+	}
+	status.Rows = rows
+	status.Players = len(players)
+	if s.minPlayersPerTeam > 0 && rows < s.minPlayersPerTeam {
+		log.Printf("Warning: %s has only %d player %s (expected at least %d); the page may not have loaded completely\n",
+			team.Name, rows, plural(rows, "row"), s.minPlayersPerTeam)
+		s.stats.update(func(rs *RunStats) { rs.SparseTeams++ })
 	}
+
+	if len(s.currencies) > 0 {
+		for i := range players {
+			players[i].Prices = map[string]int64{s.currencies[0]: players[i].PriceValue}
+		}
+		status.Attempts += s.fetchExtraPrices(ctx, team, players)
+	}
+
+	s.stats.update(func(rs *RunStats) {
+		rs.TeamsSucceeded++
+		rs.PlayersKept += int64(len(players))
+	})
+	return players, status
 }
 
-// Run starts the entire scraping process.
-func (s *Scraper) Run(teams []Team) {
+// Run starts the entire scraping process. The run stops early when ctx is
+// done or the total timeout elapses; players collected so far are still written.
+// The returned error reports a run that should be treated as failed.
+func (s *Scraper) Run(ctx context.Context, teams []Team) error {
 	startTime := time.Now()
 	log.Println("Starting player scouting...")
+	s.stats = &Stats{}
+	s.resetAgents()
+	s.configureTransport()
 
-	var wg sync.WaitGroup
+	if s.totalTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.totalTimeout)
+		defer cancel()
+	}
 
-	results := make(chan Player, len(teams)) // Buffer is still useful.
-	allPlayers := make([]Player, 0)
+	if s.skipLogFile != "" {
+		var err error
+		if s.skipLog, err = openSkipLog(s.skipLogFile); err != nil {
+			return err
+		}
+		defer func() {
+			if err := s.skipLog.Close(); err != nil {
+				log.Printf("Error writing skip log to %s: %v\n", s.skipLogFile, err)
+			}
+			s.skipLog = nil
+		}()
+	}
 
-	// It will block until the channel is closed by the goroutine above.
-	go func() {
-		for player := range results {
-			allPlayers = append(allPlayers, player)
+	if s.dumpDir != "" {
+		if err := os.MkdirAll(s.dumpDir, 0755); err != nil {
+			return fmt.Errorf("failed to create dump directory: %w", err)
 		}
-	}()
+	}
+	if s.fetchOnly != "" {
+		if err := os.MkdirAll(s.fetchOnly, 0755); err != nil {
+			return fmt.Errorf("failed to create fetch directory: %w", err)
+		}
+	}
+	if s.replayDir != "" {
+		log.Printf("Replaying saved pages from %s; no requests will be made\n", s.replayDir)
+	}
 
-	semaphore := make(chan struct{}, s.concurrency)
-	for _, team := range teams {
-		wg.Add(1)
-		semaphore <- struct{}{} // Acquire semaphore
+	// The previous results are read before anything can overwrite them,
+	// live flushing included.
+	var previous map[string]bool
+	if s.newOnly != "" {
+		var err error
+		if previous, err = previousProfiles(s.outputs[0]); err != nil {
+			return err
+		}
+		if previous == nil {
+			log.Printf("No previous results in %s; every player counts as new\n", s.outputs[0])
+		}
+	}
+
+	var store *stateStore
+	if s.stateFile != "" {
+		var err error
+		if store, err = loadState(s.stateFile); err != nil {
+			return err
+		}
+	}
 
-		go func(t Team) {
-			defer wg.Done()
-			s.processTeam(t, results)
-			<-semaphore // Release semaphore
-		}(team)
+	// Slots beyond the number of teams would never be used.
+	workers := s.workers(len(teams))
+	if workers < s.concurrency && len(teams) > 0 {
+		log.Printf("Note: -concurrency=%d is more than the %d %s to scrape; using %d %s\n",
+			s.concurrency, len(teams), plural(len(teams), "team"), workers, plural(workers, "worker"))
 	}
 
-	// Wait for the collection to finish.
-	// Since wg.Wait() happens before `close(results)`, and the collection loop
-	// only finishes after the channel is closed, we can be sure all players are collected
-	// once the main goroutine unblocks here. A better way is to use another waitgroup
-	// for the collector.
+	var wg sync.WaitGroup
+
+	results := make(chan Player, s.resultBuffer)
+	allPlayers := make([]Player, 0)
+
+	// In ordered mode each worker fills its own bucket, indexed by the team's
+	// position in the list, so no synchronization is needed beyond wg.
+	var buckets [][]Player
+	if s.ordered {
+		buckets = make([][]Player, len(teams))
+	}
+
+	// Like buckets, each worker writes only its own team's status.
+	statuses := make([]TeamStatus, len(teams))
+
+	// The collector drains results until the channel is closed below. With
+	// live flushing enabled it also rewrites the outputs as players arrive.
 	var collectorWg sync.WaitGroup
 	collectorWg.Add(1)
 	go func() {
 		defer collectorWg.Done()
-		for player := range results {
-			allPlayers = append(allPlayers, player)
+
+		var tick <-chan time.Time
+		if s.flushInterval > 0 {
+			ticker := time.NewTicker(s.flushInterval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		flushed := 0
+		for {
+			select {
+			case player, ok := <-results:
+				if !ok {
+					return
+				}
+				allPlayers = append(allPlayers, player)
+				if s.flushEvery > 0 && len(allPlayers)-flushed >= s.flushEvery {
+					s.flushOutputs(allPlayers)
+					flushed = len(allPlayers)
+				}
+			case <-tick:
+				if len(allPlayers) > flushed {
+					s.flushOutputs(allPlayers)
+					flushed = len(allPlayers)
+				}
+			}
 		}
 	}()
 
+	// deliver hands team i's players to the collector or its bucket.
+	deliver := func(i int, players []Player) {
+		if s.ordered {
+			buckets[i] = players
+		} else {
+			for _, p := range players {
+				results <- p
+			}
+		}
+	}
+
+	// skip reports whether team i should not be fetched, recording why.
+	// Teams skipped by -resume keep the players of their last scrape, so
+	// the outputs still cover every team.
+	skip := func(i int, t Team) bool {
+		if s.resume {
+			if players, ok := store.resumable(t.URL, s.freshFor, startTime); ok {
+				log.Printf("Skipping %s: scraped successfully within the last %v; keeping its %d %s from then\n",
+					t.Name, s.freshFor, len(players), plural(len(players), "player"))
+				statuses[i] = TeamStatus{Team: t.Name, URL: redactURL(t.URL), Players: len(players), Skipped: true}
+				for j := range players {
+					p := &players[j]
+					price, priced := parsePrice(p.Price)
+					_, p.valueRank = s.valueRatio(p.Potential, price, priced)
+				}
+				deliver(i, players)
+				return true
+			}
+		}
+		if ctx.Err() != nil {
+			s.debugf("Not starting %s: %v\n", t.Name, ctx.Err())
+			statuses[i] = TeamStatus{Team: t.Name, URL: redactURL(t.URL), Error: ctx.Err().Error()}
+			return true
+		}
+		return false
+	}
+
+	// work scrapes team i and hands its players to the collector or its bucket.
+	work := func(i int, t Team) {
+		players, status := s.processTeam(ctx, t)
+		statuses[i] = status
+		s.stats.recordAttempts(status.Attempts)
+		if store != nil {
+			errMsg := status.Error
+			if errMsg == "" && status.Rows == 0 {
+				// A page without rows is nothing to resume from.
+				errMsg = "no player rows found"
+			}
+			store.record(t.URL, errMsg, players, time.Now())
+		}
+		deliver(i, players)
+	}
+
+	// Teams are dispatched in this order; results still go to their
+	// positions in the list.
+	order := s.dispatchOrder(teams)
+
+	if s.partition {
+		// Deterministic assignment: contiguous chunks of the dispatch
+		// order, one goroutine each, processing its teams in order.
+		size := (len(teams) + workers - 1) / workers
+		for start := 0; start < len(teams); start += size {
+			end := min(start+size, len(teams))
+			wg.Add(1)
+			go func(start, end int) {
+				defer wg.Done()
+				// With ramp-up, chunk k starts k steps after the first.
+				if err := sleepContext(ctx, s.rampStep(workers)*time.Duration(start/size)); err != nil {
+					for _, i := range order[start:end] {
+						skip(i, teams[i])
+					}
+					return
+				}
+				for _, i := range order[start:end] {
+					if !skip(i, teams[i]) {
+						work(i, teams[i])
+					}
+				}
+			}(start, end)
+		}
+	} else {
+		semaphore := make(chan struct{}, workers)
+		s.rampUpSemaphore(ctx, semaphore)
+		for _, i := range order {
+			team := teams[i]
+			if skip(i, team) {
+				continue
+			}
+
+			select {
+			case semaphore <- struct{}{}: // Acquire semaphore
+			case <-ctx.Done():
+				skip(i, team)
+				continue
+			}
+			wg.Add(1)
+
+			go func(i int, t Team) {
+				defer wg.Done()
+				work(i, t)
+				<-semaphore // Release semaphore
+			}(i, team)
+		}
+	}
+
 	wg.Wait()
 	close(results)
 	collectorWg.Wait() // Wait for the collector to finish.
+	if ctx.Err() != nil {
+		log.Printf("Run stopped early: %v\n", ctx.Err())
+	}
+
+	// Capture-only runs have nothing to extract or write.
+	if s.fetchOnly != "" {
+		stats := s.stats.Snapshot()
+		log.Printf("\nFetching completed in %v\n", time.Since(startTime))
+		log.Printf("Saved %d of %d pages to %s (%d requests, %d failed, %d retries)\n",
+			stats.TeamsSucceeded, len(teams), s.fetchOnly, stats.Requests, stats.FailedRequests, stats.Retries)
+		return nil
+	}
+
+	for _, bucket := range buckets {
+		allPlayers = append(allPlayers, bucket...)
+	}
+
+	allPlayers, dropped := dedupePlayers(allPlayers, s.dedupe)
+	if dropped > 0 {
+		log.Printf("Dropped %d duplicate %s (-dedupe=%s)\n", dropped, plural(dropped, "player"), s.dedupe)
+	}
+
+	allPlayers, incomplete := s.requireFields(allPlayers)
+
+	s.normalizePrices(allPlayers)
+
+	if s.sortKey != "" {
+		sortPlayers(allPlayers, s.sortKey)
+	}
 
-	if err := s.writePlayersToFile(allPlayers); err != nil {
-		log.Printf("Error writing to file: %v\n", err)
+	if s.PostProcessor != nil {
+		processed, err := s.PostProcessor(allPlayers)
+		if err != nil {
+			log.Printf("Post-processing failed, writing unprocessed results: %v\n", err)
+		} else {
+			allPlayers = processed
+		}
+	}
+
+	// Pages came back with content but not a single player row could be
+	// found in any of them: the site layout has almost certainly changed,
+	// so keep the previous outputs rather than overwrite them with nothing.
+	collected := s.stats.Snapshot()
+	layoutChanged := collected.RowsSeen == 0 && collected.PagesWithBody > 0
+	if layoutChanged {
+		log.Printf("Error: %d pages were fetched but no player rows were found; the page layout may have changed. Outputs were not written.\n", collected.PagesWithBody)
+	} else if incomplete != nil {
+		log.Printf("Error: %v. Outputs were not written.\n", incomplete)
+	} else if s.summaryOnly {
+		s.debugf("Not writing player outputs with -summary-only\n")
 	} else {
-		log.Printf("Results saved to %s\n", s.outputFile)
+		if err := s.writeOutputs(allPlayers); err != nil {
+			log.Printf("Some outputs failed: %v\n", err)
+		}
+		if s.newOnly != "" {
+			s.writeNewPlayers(allPlayers, previous)
+		}
+	}
+
+	// The state of a run whose outputs were withheld is not kept either:
+	// -resume must not skip teams whose results were never written.
+	if store != nil && (layoutChanged || incomplete != nil) {
+		log.Printf("State was not saved to %s\n", s.stateFile)
+	} else if store != nil {
+		store.prune(s.stateTTL, time.Now())
+		if err := store.save(); err != nil {
+			log.Printf("Error writing state to %s: %v\n", s.stateFile, err)
+		}
+	}
+
+	stats := s.stats.Snapshot()
+	rowsSeen := stats.RowsSeen
+
+	if s.summaryFile != "" {
+		summary := RunSummary{
+			StartedAt: startTime,
+			Duration:  time.Since(startTime).String(),
+			Stats:     stats,
+			Players:   len(allPlayers),
+			Bands:     overallBands(allPlayers),
+			Teams:     statuses,
+		}
+		if err := writeSummary(s.summaryFile, summary); err != nil {
+			log.Printf("Error writing summary to %s: %v\n", s.summaryFile, err)
+		} else {
+			log.Printf("Summary saved to %s\n", s.summaryFile)
+		}
+	}
+
+	if s.leaderboard > 0 {
+		s.logLeaderboard(allPlayers, s.leaderboard)
+	}
+
+	if s.summaryOnly || s.aggregatesFile != "" {
+		aggregates := summarize(allPlayers)
+		if s.summaryOnly {
+			logSummary(aggregates)
+		}
+		if s.aggregatesFile != "" {
+			if err := writeAggregates(s.aggregatesFile, aggregates); err != nil {
+				log.Printf("Error writing aggregates to %s: %v\n", s.aggregatesFile, err)
+			} else {
+				log.Printf("Aggregates saved to %s\n", s.aggregatesFile)
+			}
+		}
+	}
+
+	if s.teamSummaryFile != "" {
+		if err := writeTeamSummary(s.teamSummaryFile, aggregateByTeam(allPlayers, len(s.rates) > 0)); err != nil {
+			log.Printf("Error writing team summary to %s: %v\n", s.teamSummaryFile, err)
+		} else {
+			log.Printf("Team summary saved to %s\n", s.teamSummaryFile)
+		}
 	}
 
 	log.Printf("\nScouting completed in %v\n", time.Since(startTime))
 	log.Printf("Found %d players with potential >= %d\n", len(allPlayers), s.minPotential)
+	log.Printf("Requests: %d (%d failed, %d retries), teams: %d ok / %d failed, rows: %d, parse failures: %d\n",
+		stats.Requests, stats.FailedRequests, stats.Retries, stats.TeamsSucceeded, stats.TeamsFailed, stats.RowsSeen, stats.ParseFailures)
+	if len(stats.AttemptsHistogram) > 0 {
+		log.Printf("Attempts per team: %s\n", formatHistogram(stats.AttemptsHistogram))
+	}
+	if len(allPlayers) > 0 {
+		log.Printf("Players by overall: %s\n", formatBands(overallBands(allPlayers)))
+	}
+	if stats.SparseTeams > 0 {
+		log.Printf("Warning: %d %s had fewer than %d player rows\n", stats.SparseTeams, plural(int(stats.SparseTeams), "team"), s.minPlayersPerTeam)
+	}
+	if stats.SalvagedRows > 0 {
+		log.Printf("Skipped %d %s that failed to parse with a panic\n", stats.SalvagedRows, plural(int(stats.SalvagedRows), "row"))
+	}
+	if len(stats.MissingFields) > 0 {
+		log.Printf("Players missing required fields: %s\n", formatMissing(stats.MissingFields))
+	}
+
+	if layoutChanged {
+		return errLayoutChanged
+	}
+	if incomplete != nil {
+		return incomplete
+	}
+
+	// Rows were parsed but nothing survived the filters: the page is fine,
+	// the criteria are too strict.
+	if len(allPlayers) == 0 && rowsSeen > 0 && (s.warnZeroAfterFilter || s.failZeroAfterFilter) {
+		log.Printf("Warning: %d player rows were found but none met the criteria; consider loosening the thresholds\n", rowsSeen)
+		if s.failZeroAfterFilter {
+			return fmt.Errorf("no players met the criteria out of %d rows", rowsSeen)
+		}
+	}
+	return nil
 }
 
 func main() {
 	scraper := NewScraper()
-	scraper.Run(teams)
+	scraper.registerFlags(flag.CommandLine)
+	flag.Parse()
+	if err := scraper.validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v\n", err)
+	}
+
+	// -explain does no I/O beyond reading a team file, so a list that would
+	// be fetched or read from stdin is shown unresolved.
+	if scraper.explainOnly && scraper.teamsFromStream() {
+		if err := scraper.explain(os.Stdout, nil); err != nil {
+			log.Fatalf("Printing configuration failed: %v\n", err)
+		}
+		return
+	}
+
+	var list []Team
+	var err error
+	if scraper.leagueURL != "" {
+		list, err = scraper.discoverTeams(context.Background())
+	} else {
+		list, err = scraper.teamList()
+	}
+	if err != nil {
+		log.Fatalf("Loading teams failed: %v\n", err)
+	}
+	list, err = scraper.selectTeams(list)
+	if err != nil {
+		log.Fatalf("Selecting teams failed: %v\n", err)
+	}
+
+	if scraper.explainOnly {
+		if err := scraper.explain(os.Stdout, list); err != nil {
+			log.Fatalf("Printing configuration failed: %v\n", err)
+		}
+		return
+	}
+
+	if scraper.poll > 0 {
+		ctx, stop := pollContext()
+		defer stop()
+		scraper.pollRuns(ctx, list)
+		return
+	}
+
+	if err := scraper.runWithRetries(context.Background(), list); err != nil {
+		log.Printf("Run failed: %v\n", err)
+		if errors.Is(err, errLayoutChanged) {
+			os.Exit(exitLayoutChanged)
+		}
+		os.Exit(exitFailure)
+	}
 }
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValidateRejectsLiveFlushing(t *testing.T) {
+	for name, set := range map[string]func(s *Scraper){
+		"ordered":      func(s *Scraper) { s.ordered = true },
+		"summary-only": func(s *Scraper) { s.summaryOnly = true },
+		"on-missing":   func(s *Scraper) { s.require, s.onMissing = []string{"age"}, missingError },
+	} {
+		s := newTestScraper(t)
+		s.flushInterval = time.Second
+		set(s)
+		if err := s.validate(); err == nil || !strings.Contains(err.Error(), "live flushing") {
+			t.Errorf("%s: validate() = %v, want a live flushing error", name, err)
+		}
+	}
+
+	s := newTestScraper(t)
+	s.flushEvery = 10
+	s.require = []string{"age"}
+	if err := s.validate(); err != nil {
+		t.Errorf("flushing with -on-missing=drop: validate() = %v", err)
+	}
+}
+
+func TestValidateOutputExtensions(t *testing.T) {
+	s := newTestScraper(t)
+	s.outputs = []string{"players.json", "players.CSV"}
+	if err := s.validate(); err != nil {
+		t.Errorf("validate() = %v", err)
+	}
+	for _, bad := range []string{"players.jsn", "players.sqlite", "players"} {
+		s.outputs = []string{"players.json", bad}
+		if err := s.validate(); err == nil || !strings.Contains(err.Error(), bad) {
+			t.Errorf("-out %s: validate() = %v, want an error naming it", bad, err)
+		}
+	}
+}
+
+func TestInconsistentPotential(t *testing.T) {
+	page := `<table>
+<tr><td>Bad Row</td><td>72</td><td>71</td><td>15</td><td>19</td><td>€800K</td></tr>
+<tr><td>John Smith</td><td>60</td><td>80</td><td>20</td><td>18</td><td>€1.2M</td></tr>
+</table>`
+
+	s := newTestScraper(t)
+	s.onInconsistent = inconsistentSkip
+	players, rows := s.extractPlayers(Team{Name: "A"}, page)
+	if got := profiles(players); rows != 2 || len(got) != 1 || got[0] != "John Smith" {
+		t.Errorf("skip: kept %v of %d rows, want [John Smith] of 2", got, rows)
+	}
+
+	s = newTestScraper(t)
+	s.onInconsistent = inconsistentClamp
+	players, _ = s.extractPlayers(Team{Name: "A"}, page)
+	if len(players) != 2 {
+		t.Fatalf("clamp: kept %d players, want 2", len(players))
+	}
+	if bad := players[0]; bad.Profile != "Bad Row" || bad.Potential != bad.Overall {
+		t.Errorf("clamp: got %s, want potential raised to overall", bad)
+	}
+}
+
+// A page listing a player twice (e.g. in two formations) yields him once;
+// rows that differ in overall or potential are different entries.
+func TestRepeatedRowsCollapse(t *testing.T) {
+	page := `<table>
+<tr><td>John Smith</td><td>60</td><td>80</td><td>20</td><td>18</td><td>€1.2M</td></tr>
+<tr><td><a href="/p/1">John  Smith</a></td><td>60</td><td>80</td><td>20</td><td>18</td><td>€1.2M</td></tr>
+<tr><td>John Smith</td><td>61</td><td>80</td><td>19</td><td>18</td><td>€1.2M</td></tr>
+</table>`
+
+	s := newTestScraper(t)
+	players, rows := s.extractPlayers(Team{Name: "A"}, page)
+	if rows != 3 {
+		t.Errorf("saw %d rows, want 3", rows)
+	}
+	if len(players) != 2 || players[0].Overall != 60 || players[1].Overall != 61 {
+		t.Errorf("got %v, want one player at 60 and one at 61", players)
+	}
+}
+
+// TestConcurrentDelays draws delays and user agents from many goroutines at
+// once, as workers do. Run it with -race: the shared random source must
+// only be used under its lock.
+func TestConcurrentDelays(t *testing.T) {
+	s := newTestScraper(t)
+	s.minDelay, s.maxDelay = time.Nanosecond, time.Microsecond
+	maxDelay := Duration(2 * time.Microsecond)
+	team := Team{Name: "A", MaxDelay: &maxDelay}
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				if err := s.politeDelay(context.Background(), team); err != nil {
+					t.Error(err)
+					return
+				}
+				_ = s.randomAgent()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkNewScraper measures constructing a scraper, which no longer
+// compiles the parsing patterns.
+func BenchmarkNewScraper(b *testing.B) {
+	for b.Loop() {
+		_ = NewScraper()
+	}
+}
+
+// BenchmarkCompileParsingPatterns measures what every NewScraper call paid
+// when each scraper compiled its own parsing patterns.
+func BenchmarkCompileParsingPatterns(b *testing.B) {
+	for b.Loop() {
+		_ = regexp.MustCompile(cellPattern.String())
+		_ = regexp.MustCompile(tagStripper.String())
+	}
+}
+
+// BenchmarkResultBuffer runs a replayed scrape of many teams with several
+// -result-buffer sizes, to show the cost of workers waiting on the
+// collector.
+func BenchmarkResultBuffer(b *testing.B) {
+	var page strings.Builder
+	page.WriteString("<html><table>\n")
+	for i := range 200 {
+		fmt.Fprintf(&page, "<tr><td>Player %d</td><td>60</td><td>80</td><td>20</td><td>18</td><td>€1.2M</td></tr>\n", i)
+	}
+	page.WriteString("</table></html>\n")
+
+	dir := b.TempDir()
+	teams := make([]Team, 50)
+	for i := range teams {
+		teams[i] = Team{Name: fmt.Sprintf("Team %d", i), URL: "http://example.invalid/"}
+		if err := os.WriteFile(filepath.Join(dir, dumpFileName(teams[i], teams[i].URL)), []byte(page.String()), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	for _, size := range []int{0, 64, len(teams) * 200} {
+		b.Run(fmt.Sprintf("buffer=%d", size), func(b *testing.B) {
+			s := newTestScraper(b)
+			s.replayDir = dir
+			s.resultBuffer = size
+			s.concurrency = 8
+			s.flushEvery = 1000 // A slow collector, as with live flushing.
+			for b.Loop() {
+				if err := s.Run(context.Background(), teams); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
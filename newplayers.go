@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// previousProfiles reads the normalized profile of every player in a result
+// file written by an earlier run, in either output format. It returns nil
+// without an error when the file does not exist yet.
+func previousProfiles(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open previous results: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var profiles []string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		profiles, err = csvProfiles(f)
+	case ".parquet":
+		profiles, err = parquetProfiles(f)
+	default:
+		var players []Player
+		if err = json.NewDecoder(f).Decode(&players); err == nil {
+			for _, p := range players {
+				profiles = append(profiles, p.Profile)
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous results from %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool, len(profiles))
+	for _, p := range profiles {
+		seen[profileKey(p)] = true
+	}
+	return seen, nil
+}
+
+// csvProfiles returns the profile column of a CSV result file.
+func csvProfiles(r io.Reader) ([]string, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	col := slices.Index(records[0], "profile")
+	if col < 0 {
+		return nil, errors.New("no profile column")
+	}
+
+	var profiles []string
+	for _, record := range records[1:] {
+		if col < len(record) {
+			profiles = append(profiles, record[col])
+		}
+	}
+	return profiles, nil
+}
+
+// parquetProfiles returns the profile column of a Parquet result file.
+func parquetProfiles(f *os.File) ([]string, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	players, err := readParquetPlayers(f, info.Size())
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]string, len(players))
+	for i, p := range players {
+		profiles[i] = p.Profile
+	}
+	return profiles, nil
+}
+
+// writeNewPlayers writes the players whose normalized profile is not in
+// previous to the -new-only file. With no previous results every player
+// counts as new.
+func (s *Scraper) writeNewPlayers(players []Player, previous map[string]bool) {
+	var fresh []Player
+	for _, p := range players {
+		// The previous results hold the names as written to the outputs.
+		profile := p.Profile
+		if s.hashProfiles {
+			profile = hashProfile(profile)
+		}
+		if !previous[profileKey(profile)] {
+			fresh = append(fresh, p)
+		}
+	}
+	if fresh == nil {
+		fresh = []Player{}
+	}
+
+	if err := writePlayersToFile(s.newOnly, s.redacted(fresh)); err != nil {
+		log.Printf("Error writing new players to %s: %v\n", s.newOnly, err)
+		return
+	}
+	log.Printf("%d new %s saved to %s\n", len(fresh), plural(len(fresh), "player"), s.newOnly)
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// OutputWriter encodes a result set to a stream in one format.
+type OutputWriter interface {
+	WritePlayers(w io.Writer, players []Player) error
+}
+
+// outputWriters maps a format name, which is also the file extension that
+// selects it, to the writer that handles it.
+var outputWriters = map[string]OutputWriter{
+	"json":    jsonWriter{},
+	"csv":     csvWriter{},
+	"parquet": parquetWriter{},
+}
+
+// WritePlayers encodes players to w in the given format ("json", "csv" or "parquet").
+// The file outputs are written through it, so any io.Writer (a buffer, a
+// socket, a gzip stream) gets exactly the same encoding.
+func WritePlayers(w io.Writer, players []Player, format string) error {
+	ow, ok := outputWriters[strings.ToLower(format)]
+	if !ok {
+		return fmt.Errorf("unknown output format %q", format)
+	}
+	return ow.WritePlayers(w, players)
+}
+
+// formatFor picks the output format for a path based on its extension.
+func formatFor(path string) (string, error) {
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	if _, ok := outputWriters[format]; !ok {
+		return "", fmt.Errorf("no output writer for extension %q", filepath.Ext(path))
+	}
+	return format, nil
+}
+
+// writePlayersToFile saves the list of players in the format matching the path.
+func writePlayersToFile(path string, players []Player) error {
+	format, err := formatFor(path)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, func(w io.Writer) error {
+		return WritePlayers(w, players, format)
+	})
+}
+
+// writeFileAtomic writes a file through a temporary sibling and renames it
+// into place, so readers never observe a partially written file.
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to move file into place: %w", err)
+	}
+	return nil
+}
+
+// jsonWriter writes players as an indented JSON array.
+type jsonWriter struct{}
+
+func (jsonWriter) WritePlayers(w io.Writer, players []Player) error {
+	// Marshal the entire slice into a valid JSON array format with indentation.
+	jsonData, err := json.MarshalIndent(players, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal players to JSON: %w", err)
+	}
+
+	_, err = w.Write(jsonData)
+	return err
+}
+
+// csvWriter writes players as CSV with a header row.
+type csvWriter struct{}
+
+func (csvWriter) WritePlayers(out io.Writer, players []Player) error {
+	// The reasons column only appears when -explain-players filled it in.
+	withReasons := false
+	for _, p := range players {
+		if len(p.Reasons) > 0 {
+			withReasons = true
+			break
+		}
+	}
+
+	w := csv.NewWriter(out)
+	header := []string{"profile", "team", "price", "price_value", "prices", "price_normalized", "value_ratio", "age", "overall", "potential", "growth", "contract_expiry", "height", "weight"}
+	if withReasons {
+		header = append(header, "reasons")
+	}
+	_ = w.Write(header)
+	for _, p := range players {
+		record := []string{
+			p.Profile,
+			p.Team,
+			p.Price,
+			strconv.FormatInt(p.PriceValue, 10),
+			formatPrices(p.Prices),
+			strconv.FormatInt(p.PriceNormalized, 10),
+			strconv.FormatFloat(p.ValueRatio, 'f', 2, 64),
+			strconv.Itoa(p.Age),
+			strconv.Itoa(p.Overall),
+			strconv.Itoa(p.Potential),
+			strconv.Itoa(p.Growth),
+			p.ContractExpiry,
+			strconv.Itoa(p.Height),
+			strconv.Itoa(p.Weight),
+		}
+		if withReasons {
+			record = append(record, strings.Join(p.Reasons, "; "))
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// checkOutput reads a written output back and confirms it decodes and holds
+// want players, for -validate-output. It catches encoding bugs and partial
+// writes before anything downstream reads the file.
+func checkOutput(path string, want int) error {
+	format, err := formatFor(path)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to reopen output: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var got int
+	switch format {
+	case "csv":
+		records, err := csv.NewReader(f).ReadAll()
+		if err != nil {
+			return fmt.Errorf("output is not valid CSV: %w", err)
+		}
+		if len(records) == 0 {
+			return fmt.Errorf("output has no CSV header")
+		}
+		got = len(records) - 1
+	case "parquet":
+		info, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("failed to reopen output: %w", err)
+		}
+		players, err := readParquetPlayers(f, info.Size())
+		if err != nil {
+			return fmt.Errorf("output is not valid Parquet: %w", err)
+		}
+		got = len(players)
+	default:
+		var players []Player
+		if err := json.NewDecoder(f).Decode(&players); err != nil {
+			return fmt.Errorf("output is not valid JSON: %w", err)
+		}
+		got = len(players)
+	}
+	if got != want {
+		return fmt.Errorf("output holds %d players, want %d", got, want)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetPlayer is the Parquet row for a Player: one typed column per field,
+// with the per-currency prices as a map and the reasons as a list.
+type parquetPlayer struct {
+	Profile         string           `parquet:"profile"`
+	Team            string           `parquet:"team"`
+	Price           string           `parquet:"price"`
+	PriceValue      int64            `parquet:"price_value"`
+	Prices          map[string]int64 `parquet:"prices"`
+	PriceNormalized int64            `parquet:"price_normalized"`
+	ValueRatio      float64          `parquet:"value_ratio"`
+	Age             int32            `parquet:"age"`
+	Overall         int32            `parquet:"overall"`
+	Potential       int32            `parquet:"potential"`
+	Growth          int32            `parquet:"growth"`
+	ContractExpiry  string           `parquet:"contract_expiry"`
+	ContractYear    int32            `parquet:"contract_year"`
+	Height          int32            `parquet:"height"`
+	Weight          int32            `parquet:"weight"`
+	Reasons         []string         `parquet:"reasons,list"`
+}
+
+func toParquet(p Player) parquetPlayer {
+	return parquetPlayer{
+		Profile:         p.Profile,
+		Team:            p.Team,
+		Price:           p.Price,
+		PriceValue:      p.PriceValue,
+		Prices:          p.Prices,
+		PriceNormalized: p.PriceNormalized,
+		ValueRatio:      p.ValueRatio,
+		Age:             int32(p.Age),
+		Overall:         int32(p.Overall),
+		Potential:       int32(p.Potential),
+		Growth:          int32(p.Growth),
+		ContractExpiry:  p.ContractExpiry,
+		ContractYear:    int32(p.ContractYear),
+		Height:          int32(p.Height),
+		Weight:          int32(p.Weight),
+		Reasons:         p.Reasons,
+	}
+}
+
+func (r parquetPlayer) player() Player {
+	return Player{
+		Profile:         r.Profile,
+		Team:            r.Team,
+		Price:           r.Price,
+		PriceValue:      r.PriceValue,
+		Prices:          r.Prices,
+		PriceNormalized: r.PriceNormalized,
+		ValueRatio:      r.ValueRatio,
+		Age:             int(r.Age),
+		Overall:         int(r.Overall),
+		Potential:       int(r.Potential),
+		Growth:          int(r.Growth),
+		ContractExpiry:  r.ContractExpiry,
+		ContractYear:    int(r.ContractYear),
+		Height:          int(r.Height),
+		Weight:          int(r.Weight),
+		Reasons:         r.Reasons,
+	}
+}
+
+// parquetWriter writes players as a Parquet file, for loading into columnar
+// analytics tools.
+type parquetWriter struct{}
+
+func (parquetWriter) WritePlayers(w io.Writer, players []Player) error {
+	rows := make([]parquetPlayer, len(players))
+	for i, p := range players {
+		rows[i] = toParquet(p)
+	}
+	if err := parquet.Write(w, rows); err != nil {
+		return fmt.Errorf("failed to write Parquet: %w", err)
+	}
+	return nil
+}
+
+// readParquetPlayers reads back the players of a Parquet output.
+func readParquetPlayers(r io.ReaderAt, size int64) ([]Player, error) {
+	rows, err := parquet.Read[parquetPlayer](r, size)
+	if err != nil {
+		return nil, err
+	}
+	players := make([]Player, len(rows))
+	for i, row := range rows {
+		players[i] = row.player()
+	}
+	return players, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParquetRoundTrip(t *testing.T) {
+	players := []Player{
+		{
+			Profile: "John Smith", Team: "Alpha", Price: "€1.5M", PriceValue: 1500000,
+			Prices: map[string]int64{"EUR": 1500000, "GBP": 1300000}, PriceNormalized: 1500000,
+			ValueRatio: 53.33, Age: 19, Overall: 60, Potential: 80, Growth: 20,
+			ContractExpiry: "30 Jun 2027", ContractYear: 2027, Height: 182, Weight: 75,
+			Reasons: []string{"potential 80 >= 75", "age 19 <= 21"},
+		},
+		{Profile: "Free Lad", Team: "Beta", Price: "Free", Age: 18, Overall: 50, Potential: 72, Growth: 22},
+	}
+	path := filepath.Join(t.TempDir(), "players.parquet")
+	if err := writePlayersToFile(path, players); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := readParquetPlayers(f, info.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(players) {
+		t.Fatalf("read %d players, want %d", len(got), len(players))
+	}
+	if !reflect.DeepEqual(got[0], players[0]) {
+		t.Errorf("round trip = %+v, want %+v", got[0], players[0])
+	}
+	// Empty maps and lists may come back as nil or empty; the values must not change.
+	if p := got[1]; p.Profile != "Free Lad" || p.Potential != 72 || p.PriceValue != 0 || len(p.Prices) != 0 || len(p.Reasons) != 0 {
+		t.Errorf("round trip = %+v, want %+v", p, players[1])
+	}
+
+	if err := checkOutput(path, len(players)); err != nil {
+		t.Errorf("checkOutput() = %v", err)
+	}
+	previous, err := previousProfiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !previous[profileKey("john smith")] || !previous[profileKey("Free Lad")] {
+		t.Errorf("previousProfiles() = %v, want both players", previous)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import "time"
+
+// maxPenalty caps how far -error-backoff-multiplier can stretch the delay.
+const maxPenalty = 16.0
+
+// penalized scales a politeness delay by the current error penalty.
+func (s *Scraper) penalized(delay time.Duration) time.Duration {
+	s.penaltyMu.Lock()
+	defer s.penaltyMu.Unlock()
+	return time.Duration(float64(delay) * s.penalty)
+}
+
+// recordOutcome adjusts the shared error penalty after a request. Each
+// failure multiplies it by the configured factor; each success divides it
+// again, so the delay decays back to normal once the site recovers.
+func (s *Scraper) recordOutcome(failed bool) {
+	if s.errorBackoff <= 1 {
+		return
+	}
+
+	s.penaltyMu.Lock()
+	defer s.penaltyMu.Unlock()
+
+	if failed {
+		s.penalty = min(s.penalty*s.errorBackoff, maxPenalty)
+	} else {
+		s.penalty = max(s.penalty/s.errorBackoff, 1)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// pollRuns repeats the scrape over teams with -poll: each run writes its
+// outputs as usual, then the next one starts once the interval has passed
+// since the previous one finished, so runs never overlap. A failed run is
+// logged and polling carries on until ctx is done.
+func (s *Scraper) pollRuns(ctx context.Context, teams []Team) {
+	run := 0
+	for ctx.Err() == nil {
+		run++
+		log.Printf("Starting run %d\n", run)
+		if err := s.Run(ctx, teams); err != nil {
+			log.Printf("Run %d failed: %v\n", run, err)
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+		log.Printf("Run %d done; next run in %v\n", run, s.poll)
+		if err := sleepContext(ctx, s.poll); err != nil {
+			break
+		}
+	}
+	log.Printf("Polling stopped after %d %s\n", run, plural(run, "run"))
+}
+
+// pollContext returns a context that is cancelled by the first interrupt or
+// SIGTERM, so polling stops cleanly. Later signals get their default
+// behaviour back, so a second Ctrl-C exits immediately.
+func pollContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+	return ctx, stop
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parsePrice converts a displayed price such as "€1.5M", "£850K" or "Free"
+// into a whole number of currency units. The second return value is false
+// when the text could not be understood.
+func parsePrice(text string) (int64, bool) {
+	t := strings.TrimSpace(text)
+	if strings.EqualFold(t, "free") {
+		return 0, true
+	}
+
+	t = strings.TrimLeft(t, "£€$ ")
+	t = strings.ReplaceAll(t, ",", "")
+	if t == "" {
+		return 0, false
+	}
+
+	multiplier := 1.0
+	switch strings.ToUpper(t[len(t)-1:]) {
+	case "K":
+		multiplier = 1e3
+	case "M":
+		multiplier = 1e6
+	case "B":
+		multiplier = 1e9
+	}
+	if multiplier != 1 {
+		t = t[:len(t)-1]
+	}
+
+	value, err := strconv.ParseFloat(t, 64)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+	return int64(math.Round(value * multiplier)), true
+}
+
+// priceBound is a flag.Value for an optional price limit. It accepts the same
+// notation as the site ("1.5M", "750K", "Free") or a plain number.
+type priceBound struct {
+	value int64
+	set   bool
+}
+
+func (b *priceBound) String() string {
+	if !b.set {
+		return ""
+	}
+	return strconv.FormatInt(b.value, 10)
+}
+
+func (b *priceBound) Set(text string) error {
+	value, ok := parsePrice(text)
+	if !ok {
+		return fmt.Errorf("invalid price %q", text)
+	}
+	b.value, b.set = value, true
+	return nil
+}
+
+// priceInRange reports whether a player's price satisfies the configured bounds.
+// Free players have a price of zero, so any positive minimum excludes them.
+// Players whose price could not be parsed are excluded whenever a bound is set.
+func (s *Scraper) priceInRange(value int64, parsed bool) bool {
+	if !s.minPrice.set && !s.maxPrice.set {
+		return true
+	}
+	if !parsed {
+		return false
+	}
+	if s.minPrice.set && value < s.minPrice.value {
+		return false
+	}
+	if s.maxPrice.set && value > s.maxPrice.value {
+		return false
+	}
+	return true
+}
+
+// Policies accepted by -free-value for players whose price is zero.
+const (
+	freeInfinite = "infinite" // Free players count as the best possible value.
+	freeExclude  = "exclude"  // Free players count as having no value ratio.
+)
+
+// valueRatio returns a player's potential per million of price and the rank
+// used to filter and sort by value. Free players have no finite ratio, so
+// their rank follows the -free-value policy; unpriced players rank lowest.
+func (s *Scraper) valueRatio(potential int, price int64, priced bool) (float64, float64) {
+	switch {
+	case !priced:
+		return 0, -1
+	case price == 0 && s.freeValue == freeInfinite:
+		return 0, math.Inf(1)
+	case price == 0:
+		return 0, -1
+	}
+	ratio := float64(potential) / (float64(price) / 1e6)
+	return ratio, ratio
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// hashProfile replaces a player name with the first 8 hex digits of the
+// SHA-256 of its normalized form, for -hash-profiles. Names that differ only
+// in case or spacing hash alike, so deduplication and diffs still line up.
+// The hash cannot be reversed.
+func hashProfile(profile string) string {
+	sum := sha256.Sum256([]byte(profileKey(profile)))
+	return hex.EncodeToString(sum[:4])
+}
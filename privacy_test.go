@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestHashProfileIsStable(t *testing.T) {
+	a := hashProfile("John Smith")
+	if len(a) != 8 {
+		t.Fatalf("hashProfile() = %q, want 8 hex digits", a)
+	}
+	for _, name := range []string{"John Smith", "john smith", "  John   Smith "} {
+		if got := hashProfile(name); got != a {
+			t.Errorf("hashProfile(%q) = %q, want %q", name, got, a)
+		}
+	}
+	if hashProfile("Jon Smith") == a {
+		t.Error("different names hash alike")
+	}
+}
+
+// Names are hashed only as the outputs are written, so the extra currency
+// pages still match each player by name.
+func TestHashProfilesWithCurrencies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		price := "£1M"
+		if r.URL.Query().Get("currency") == "EUR" {
+			price = "€1.2M"
+		}
+		_, _ = w.Write([]byte(`<table><tr><td>John Smith</td><td>60</td><td>80</td><td>20</td><td>18</td><td>` + price + `</td></tr></table>`))
+	}))
+	defer srv.Close()
+
+	s := newTestScraper(t)
+	s.hashProfiles = true
+	s.currencies = []string{"GBP", "EUR"}
+	if err := s.Run(context.Background(), []Team{{Name: "A", URL: srv.URL + "/team/a"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(s.outputs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var players []Player
+	if err := json.Unmarshal(data, &players); err != nil {
+		t.Fatal(err)
+	}
+	if len(players) != 1 {
+		t.Fatalf("got %d players, want 1", len(players))
+	}
+	p := players[0]
+	if p.Profile != hashProfile("John Smith") {
+		t.Errorf("profile = %q, want the hash", p.Profile)
+	}
+	if p.Prices["GBP"] != 1000000 || p.Prices["EUR"] != 1200000 {
+		t.Errorf("prices = %v, want both currencies", p.Prices)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// rampStep is the interval between successive increases of the effective
+// concurrency up to workers during -ramp-up, or zero when ramp-up is
+// disabled.
+func (s *Scraper) rampStep(workers int) time.Duration {
+	if s.rampUp <= 0 || workers <= 1 {
+		return 0
+	}
+	return s.rampUp / time.Duration(workers-1)
+}
+
+// rampUpSemaphore implements a slow start: it fills all but one slot of the
+// semaphore with placeholder tokens and releases them one at a time over the
+// ramp-up period, so the effective concurrency grows from 1 to its full value.
+func (s *Scraper) rampUpSemaphore(ctx context.Context, semaphore chan struct{}) {
+	step := s.rampStep(cap(semaphore))
+	if step == 0 {
+		return
+	}
+
+	reserved := cap(semaphore) - 1
+	for range reserved {
+		semaphore <- struct{}{}
+	}
+
+	go func() {
+		ticker := time.NewTicker(step)
+		defer ticker.Stop()
+
+		for released := 0; released < reserved; released++ {
+			select {
+			case <-ticker.C:
+				<-semaphore
+				s.debugf("Ramp-up: concurrency now %d\n", released+2)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
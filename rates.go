@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// currencySymbols maps the symbols shown in prices to currency codes.
+var currencySymbols = map[string]string{
+	"£": "GBP",
+	"€": "EUR",
+	"$": "USD",
+}
+
+// rateTable is a flag.Value of exchange rates such as "EUR=1.17,USD=1.27",
+// each giving how many units of that currency equal one unit of the base.
+type rateTable map[string]float64
+
+func (r rateTable) String() string {
+	pairs := make([]string, 0, len(r))
+	for code, rate := range r {
+		pairs = append(pairs, code+"="+strconv.FormatFloat(rate, 'f', -1, 64))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (r rateTable) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		code, rate, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return fmt.Errorf("invalid rate %q (want CODE=rate)", pair)
+		}
+		f, err := strconv.ParseFloat(rate, 64)
+		if err != nil || f <= 0 {
+			return fmt.Errorf("invalid rate for %s: %q", code, rate)
+		}
+		r[strings.ToUpper(strings.TrimSpace(code))] = f
+	}
+	return nil
+}
+
+// priceCurrency returns the currency of a player's primary price: the first
+// -currencies entry when set, otherwise the one implied by its symbol.
+func (s *Scraper) priceCurrency(p Player) string {
+	if len(s.currencies) > 0 {
+		return strings.ToUpper(s.currencies[0])
+	}
+	for symbol, code := range currencySymbols {
+		if strings.HasPrefix(strings.TrimSpace(p.Price), symbol) {
+			return code
+		}
+	}
+	return ""
+}
+
+// normalizePrices fills PriceNormalized with each player's price converted
+// to the base currency. Prices in a currency without a rate are left at zero
+// and reported once per currency.
+func (s *Scraper) normalizePrices(players []Player) {
+	if len(s.rates) == 0 {
+		return
+	}
+	base := strings.ToUpper(s.baseCurrency)
+
+	warned := make(map[string]bool)
+	for i := range players {
+		if players[i].PriceValue == 0 {
+			continue // Free is free in any currency.
+		}
+		currency := s.priceCurrency(players[i])
+		rate, ok := s.rates[currency]
+		if currency == base {
+			rate, ok = 1, true
+		}
+		if !ok {
+			if !warned[currency] {
+				log.Printf("Warning: no rate for currency %q, normalized prices left at zero\n", currency)
+				warned[currency] = true
+			}
+			continue
+		}
+		players[i].PriceNormalized = int64(math.Round(float64(players[i].PriceValue) / rate))
+	}
+}
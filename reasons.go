@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// keepReasons lists the filters a kept player cleared and by how much, for
+// -explain-players. Filters that are disabled are left out.
+func (s *Scraper) keepReasons(p Player) []string {
+	reasons := []string{
+		fmt.Sprintf("potential %d (+%d over min %d)", p.Potential, p.Potential-s.minPotential, s.minPotential),
+		fmt.Sprintf("growth %d (+%d over min %d)", p.Growth, p.Growth-s.minGrowth, s.minGrowth),
+	}
+	if s.minPrice.set {
+		reasons = append(reasons, fmt.Sprintf("price %d (+%d over min %d)", p.PriceValue, p.PriceValue-s.minPrice.value, s.minPrice.value))
+	}
+	if s.maxPrice.set {
+		reasons = append(reasons, fmt.Sprintf("price %d (%d under max %d)", p.PriceValue, s.maxPrice.value-p.PriceValue, s.maxPrice.value))
+	}
+	if s.minValueRatio > 0 {
+		if math.IsInf(p.valueRank, 1) {
+			reasons = append(reasons, fmt.Sprintf("value ratio unbounded (free, over min %v)", s.minValueRatio))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("value ratio %.2f (+%.2f over min %v)", p.valueRank, p.valueRank-s.minValueRatio, s.minValueRatio))
+		}
+	}
+	if s.contractBefore > 0 {
+		reasons = append(reasons, fmt.Sprintf("contract %d (%d %s before %d)", p.ContractYear, s.contractBefore-p.ContractYear, plural(s.contractBefore-p.ContractYear, "year"), s.contractBefore))
+	}
+	if s.minHeight > 0 {
+		reasons = append(reasons, fmt.Sprintf("height %d (+%d over min %d)", p.Height, p.Height-s.minHeight, s.minHeight))
+	}
+	return reasons
+}
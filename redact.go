@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// redactableFields maps each field -redact accepts to the function that
+// blanks it (and anything it can be derived from) in a player.
+var redactableFields = map[string]func(p *Player){
+	"price": func(p *Player) {
+		p.Price, p.PriceValue, p.Prices, p.PriceNormalized = "", 0, nil, 0
+		p.ValueRatio = 0 // Potential per million would give the price away.
+	},
+	"age": func(p *Player) { p.Age = 0 },
+	"contract": func(p *Player) {
+		p.ContractExpiry, p.ContractYear = "", 0
+	},
+	"height": func(p *Player) { p.Height = 0 },
+	"weight": func(p *Player) { p.Weight = 0 },
+}
+
+// validateRedact checks the -redact fields.
+func (s *Scraper) validateRedact() error {
+	for _, field := range s.redact {
+		if _, ok := redactableFields[field]; !ok {
+			names := make([]string, 0, len(redactableFields))
+			for name := range redactableFields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown -redact field %q (want one of %s)", field, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// redacted returns a copy of players with the -redact fields blanked and,
+// with -hash-profiles, the names hashed, for serialization; the players
+// themselves are left intact for the rest of the run, so the extra
+// -currencies pages and dedupe still match on the real names. Reasons from
+// -explain-players quote the values, so they are dropped along with
+// redacted fields.
+func (s *Scraper) redacted(players []Player) []Player {
+	if len(s.redact) == 0 && !s.hashProfiles {
+		return players
+	}
+
+	out := make([]Player, len(players))
+	for i, p := range players {
+		for _, field := range s.redact {
+			redactableFields[field](&p)
+		}
+		if len(s.redact) > 0 {
+			p.Reasons = nil
+		}
+		if s.hashProfiles {
+			p.Profile = hashProfile(p.Profile)
+		}
+		out[i] = p
+	}
+	return out
+}
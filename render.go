@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// browserNames are the executables tried, in order, when -chrome-path is not set.
+var browserNames = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "chrome"}
+
+// errNoBrowser reports that no headless browser could be found.
+var errNoBrowser = errors.New("no headless browser found (set -chrome-path)")
+
+// browserPath returns the browser executable to use for -render.
+func (s *Scraper) browserPath() (string, error) {
+	if s.chromePath != "" {
+		return exec.LookPath(s.chromePath)
+	}
+	for _, name := range browserNames {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", errNoBrowser
+}
+
+// renderHTML loads url in a headless browser on behalf of team and returns
+// the DOM after scripts have run, for rosters that are rendered client-side.
+// Like a static fetch it waits the politeness delay first and counts toward
+// the request stats and the error penalty. The browser runs as a separate
+// process, so it adds no dependency to the build.
+func (s *Scraper) renderHTML(ctx context.Context, team Team, url string) (html string, err error) {
+	browser, err := s.browserPath()
+	if err != nil {
+		return "", err
+	}
+
+	var started time.Time
+	defer func(parent context.Context) {
+		s.recordRequest(parent, started, err)
+	}(ctx)
+
+	if err := s.politeDelay(ctx, team); err != nil {
+		return "", err
+	}
+
+	if s.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.requestTimeout)
+		defer cancel()
+	}
+
+	args := []string{"--headless", "--disable-gpu"}
+	if s.renderNoSandbox {
+		// Only on request: the pages are untrusted, and some containers
+		// cannot run the browser's sandbox at all.
+		args = append(args, "--no-sandbox")
+	}
+	args = append(args, "--user-agent="+s.userAgentFor(url), "--dump-dom", url)
+	cmd := exec.CommandContext(ctx, browser, args...)
+	started = time.Now()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("headless render failed: %w", err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeBrowser writes a stand-in for a headless browser that prints a roster
+// with the URL it was asked to load as the player's name.
+func fakeBrowser(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "browser")
+	script := `#!/bin/sh
+echo "$@" > "$0.args"
+for arg; do url=$arg; done
+echo "<table><tr><td>$url</td><td>60</td><td>80</td><td>20</td><td>18</td><td>€1.2M</td></tr></table>"
+`
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// The render fallback loads the same page as the static fetch, currency
+// included, and counts as a request.
+func TestRenderFallbackUsesPageURL(t *testing.T) {
+	srv := servePage(t, "<html><div id=\"app\"></div></html>")
+	s := newTestScraper(t)
+	s.render = true
+	s.chromePath = fakeBrowser(t)
+	s.currencies = []string{"EUR"}
+	team := Team{Name: "A", URL: srv.URL + "/team/a"}
+
+	players, status := s.processTeam(context.Background(), team)
+	if status.Error != "" {
+		t.Fatal(status.Error)
+	}
+	if len(players) != 1 {
+		t.Fatalf("got %d players, want 1", len(players))
+	}
+	if !strings.Contains(players[0].Profile, "currency=EUR") {
+		t.Errorf("rendered %q, want the EUR page", players[0].Profile)
+	}
+	if got := s.stats.Snapshot().Requests; got != 2 {
+		t.Errorf("counted %d requests, want 2 (static and rendered)", got)
+	}
+}
+
+// The browser keeps its sandbox unless -render-no-sandbox asks otherwise.
+func TestRenderSandbox(t *testing.T) {
+	srv := servePage(t, "<html><div id=\"app\"></div></html>")
+	for _, noSandbox := range []bool{false, true} {
+		s := newTestScraper(t)
+		s.render, s.renderNoSandbox = true, noSandbox
+		s.chromePath = fakeBrowser(t)
+		if _, err := s.renderHTML(context.Background(), Team{Name: "A"}, srv.URL); err != nil {
+			t.Fatal(err)
+		}
+		args, err := os.ReadFile(s.chromePath + ".args")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(string(args), "--no-sandbox"); got != noSandbox {
+			t.Errorf("-render-no-sandbox=%v: browser args %q", noSandbox, args)
+		}
+	}
+
+	s := newTestScraper(t)
+	s.renderNoSandbox = true
+	if err := s.validate(); err == nil {
+		t.Error("validate() accepted -render-no-sandbox without -render")
+	}
+}
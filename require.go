@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Policies accepted by -on-missing for players lacking a -require field.
+const (
+	missingDrop  = "drop"  // Leave the player out of the results.
+	missingError = "error" // Fail the run without writing outputs.
+)
+
+// requirableFields maps each field -require accepts to a check that the
+// player has a value for it. Fields that every kept player always has, such
+// as potential, are not listed.
+var requirableFields = map[string]func(p Player) bool{
+	"profile":         func(p Player) bool { return strings.TrimSpace(p.Profile) != "" },
+	"price":           func(p Player) bool { return strings.TrimSpace(p.Price) != "" },
+	"prices":          func(p Player) bool { return len(p.Prices) > 0 },
+	"age":             func(p Player) bool { return p.Age > 0 },
+	"overall":         func(p Player) bool { return p.Overall > 0 },
+	"contract_expiry": func(p Player) bool { return strings.TrimSpace(p.ContractExpiry) != "" },
+	"contract_year":   func(p Player) bool { return p.ContractYear > 0 },
+	"height":          func(p Player) bool { return p.Height > 0 },
+	"weight":          func(p Player) bool { return p.Weight > 0 },
+}
+
+// validateRequire checks the -require fields and the -on-missing policy.
+func (s *Scraper) validateRequire() error {
+	for _, field := range s.require {
+		if _, ok := requirableFields[field]; !ok {
+			names := make([]string, 0, len(requirableFields))
+			for name := range requirableFields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown -require field %q (want one of %s)", field, strings.Join(names, ", "))
+		}
+	}
+	switch s.onMissing {
+	case missingDrop, missingError:
+		return nil
+	}
+	return fmt.Errorf("unknown -on-missing policy %q (want %s or %s)", s.onMissing, missingDrop, missingError)
+}
+
+// requireFields removes the players that lack any -require field, counting
+// each missing field in the run stats. Under the error policy the players
+// are kept and an error reports how many are incomplete.
+func (s *Scraper) requireFields(players []Player) ([]Player, error) {
+	if len(s.require) == 0 {
+		return players, nil
+	}
+
+	kept := players[:0]
+	incomplete := 0
+	for _, p := range players {
+		var missing []string
+		for _, field := range s.require {
+			if !requirableFields[field](p) {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) == 0 {
+			kept = append(kept, p)
+			continue
+		}
+
+		incomplete++
+		s.debugf("%v is missing %s\n", p, strings.Join(missing, ", "))
+		s.stats.update(func(rs *RunStats) {
+			if rs.MissingFields == nil {
+				rs.MissingFields = make(map[string]int64)
+			}
+			for _, field := range missing {
+				rs.MissingFields[field]++
+			}
+		})
+		if s.onMissing == missingError {
+			kept = append(kept, p)
+		}
+	}
+
+	if incomplete == 0 {
+		return kept, nil
+	}
+	if s.onMissing == missingError {
+		return kept, fmt.Errorf("%d %s missing required fields", incomplete, plural(incomplete, "player"))
+	}
+	log.Printf("Dropped %d %s missing required fields\n", incomplete, plural(incomplete, "player"))
+	return kept, nil
+}
+
+// formatMissing renders missing-field counts as "age: 2, price: 1".
+func formatMissing(counts map[string]int64) string {
+	fields := make([]string, 0, len(counts))
+	for field := range counts {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = fmt.Sprintf("%s: %d", field, counts[field])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// statusError reports a response with an unexpected HTTP status.
+type statusError struct {
+	code   int
+	status string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("HTTP request failed with status: %s", e.status)
+}
+
+// retryable reports whether a failed fetch is worth trying again. Server
+// errors, rate limiting and network failures are; other client errors and
+// cancellation of the caller's context are not.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code == http.StatusTooManyRequests || se.code >= 500
+	}
+	return true
+}
+
+// Limits on retrying a team: more attempts than maxRetries only add load on
+// a site that is already failing, and the doubling backoff stops growing at
+// maxRetryBackoff (or the configured base, if that is longer).
+const (
+	maxRetries      = 10
+	maxRetryBackoff = 10 * time.Minute
+)
+
+// retryDelay returns the backoff before the given retry (1-based), doubling
+// each time from the configured base up to the cap.
+func (s *Scraper) retryDelay(retry int) time.Duration {
+	limit := max(maxRetryBackoff, s.retryBackoff)
+	delay := s.retryBackoff
+	for range retry - 1 {
+		if delay >= limit/2 {
+			return limit
+		}
+		delay *= 2
+	}
+	return delay
+}
+
+// fetchWithRetry fetches url for team, retrying retryable failures up to s.retries
+// times. It returns the body, the response headers and the number of
+// attempts made. A non-nil rows may receive the page's rows while it is
+// read instead; see fetchHTML.
+func (s *Scraper) fetchWithRetry(ctx context.Context, team Team, url string, rows *rowParser) (string, http.Header, int, error) {
+	attempts := 0
+	for {
+		attempts++
+		html, header, err := s.fetchHTML(ctx, team, url, rows)
+		if err == nil {
+			return html, header, attempts, nil
+		}
+		if attempts > s.retries || !retryable(ctx, err) {
+			return "", nil, attempts, err
+		}
+
+		s.stats.update(func(rs *RunStats) { rs.Retries++ })
+		delay := s.retryDelay(attempts)
+		log.Printf("Attempt %d for %s failed (%v), retrying in %v\n", attempts, redactURL(url), err, delay)
+		if err := sleepContext(ctx, delay); err != nil {
+			return "", nil, attempts, err
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryDelayIsCapped(t *testing.T) {
+	s := newTestScraper(t)
+	s.retryBackoff = 5 * time.Second
+	for retry, want := range map[int]time.Duration{
+		1:   5 * time.Second,
+		2:   10 * time.Second,
+		4:   40 * time.Second,
+		8:   maxRetryBackoff,
+		40:  maxRetryBackoff,
+		100: maxRetryBackoff,
+	} {
+		if got := s.retryDelay(retry); got != want {
+			t.Errorf("retryDelay(%d) = %v, want %v", retry, got, want)
+		}
+	}
+
+	// A base above the cap is used as given, without doubling.
+	s.retryBackoff = time.Hour
+	if got := s.retryDelay(3); got != time.Hour {
+		t.Errorf("retryDelay(3) with a 1h base = %v, want 1h", got)
+	}
+}
+
+func TestValidateRetries(t *testing.T) {
+	for retries, ok := range map[int]bool{0: true, maxRetries: true, -1: false, maxRetries + 1: false} {
+		s := newTestScraper(t)
+		s.retries = retries
+		if err := s.validate(); (err == nil) != ok {
+			t.Errorf("-retries=%d: validate() = %v", retries, err)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	ctx := context.Background()
+	for code, want := range map[int]bool{
+		http.StatusTooManyRequests:     true,
+		http.StatusServiceUnavailable:  true,
+		http.StatusNotFound:            false,
+		http.StatusUnauthorized:        false,
+		http.StatusInternalServerError: true,
+	} {
+		if got := retryable(ctx, &statusError{code: code}); got != want {
+			t.Errorf("retryable(%d) = %v, want %v", code, got, want)
+		}
+	}
+	if !retryable(ctx, errors.New("connection reset")) {
+		t.Error("network error not retryable")
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if retryable(cancelled, errors.New("context canceled")) {
+		t.Error("cancelled fetch retryable")
+	}
+}
+
+// The retry and debug log lines name the page without its credentials.
+func TestRetryLogHidesCredentials(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(rosterPage))
+	}))
+	t.Cleanup(srv.Close)
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(io.Discard)
+
+	s := newTestScraper(t)
+	s.retries = 1
+	s.debug = true
+	pageURL := strings.Replace(srv.URL, "http://", "http://scout:s3cret@", 1)
+	if _, _, _, err := s.fetchWithRetry(context.Background(), Team{Name: "A"}, pageURL, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Attempt 1") {
+		t.Fatalf("no retry logged:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "s3cret") {
+		t.Errorf("log reveals the password:\n%s", buf.String())
+	}
+}
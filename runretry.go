@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+// errNoTeamsSucceeded reports a run in which every team that was tried
+// failed, which usually means the site or the network was down.
+var errNoTeamsSucceeded = errors.New("no team was scraped successfully")
+
+// runWithRetries runs the scrape once and, with -run-retries, runs it again
+// after -run-retry-delay while a run ends with no successful team. Teams
+// skipped as fresh by -resume do not count as failures. Once the retries are
+// used up the last run's error, or errNoTeamsSucceeded, is returned.
+func (s *Scraper) runWithRetries(ctx context.Context, teams []Team) error {
+	if s.runRetries == 0 {
+		return s.Run(ctx, teams)
+	}
+
+	attempts := s.runRetries + 1
+	for attempt := 1; ; attempt++ {
+		log.Printf("Starting run attempt %d of %d\n", attempt, attempts)
+		err := s.Run(ctx, teams)
+		stats := s.stats.Snapshot()
+		if stats.TeamsSucceeded > 0 || stats.TeamsFailed == 0 {
+			return err
+		}
+		if err == nil {
+			err = errNoTeamsSucceeded
+		}
+		if attempt == attempts {
+			return err
+		}
+
+		log.Printf("Run attempt %d of %d failed: all %d %s failed; retrying in %v\n",
+			attempt, attempts, stats.TeamsFailed, plural(int(stats.TeamsFailed), "team"), s.runRetryDelay)
+		if sleepContext(ctx, s.runRetryDelay) != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Reasons recorded in the skip log.
+const (
+	skipShortRow     = "short-row"
+	skipLoan         = "loan"
+	skipParseFailure = "parse-failure"
+	skipInconsistent = "inconsistent"
+	skipLowPotential = "low-potential"
+	skipLowGrowth    = "low-growth"
+	skipPrice        = "price-out-of-range"
+	skipValueRatio   = "low-value-ratio"
+	skipContract     = "contract-not-expiring"
+	skipDuplicate    = "duplicate"
+	skipHeight       = "below-min-height"
+)
+
+// skipRecord is one line of the skip log.
+type skipRecord struct {
+	Team   string   `json:"team"`
+	Reason string   `json:"reason"`
+	Cells  []string `json:"cells"`
+}
+
+// skipLog writes a JSONL audit of every row the parser discarded. It is
+// shared by all workers.
+type skipLog struct {
+	mu  sync.Mutex
+	f   *os.File
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+// openSkipLog creates (or truncates) the skip log at path.
+func openSkipLog(path string) (*skipLog, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create skip log: %w", err)
+	}
+	buf := bufio.NewWriter(f)
+	return &skipLog{f: f, buf: buf, enc: json.NewEncoder(buf)}, nil
+}
+
+// write appends one record.
+func (l *skipLog) write(rec skipRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_ = l.enc.Encode(rec)
+}
+
+// Close flushes and closes the log file.
+func (l *skipLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.buf.Flush(); err != nil {
+		_ = l.f.Close()
+		return err
+	}
+	return l.f.Close()
+}
+
+// logSkip records a discarded row when -skip-log is enabled.
+func (s *Scraper) logSkip(team Team, reason string, cols [][]string) {
+	if s.skipLog == nil {
+		return
+	}
+	cells := make([]string, len(cols))
+	for i, c := range cols {
+		cells[i] = s.stripTags(c[1])
+	}
+	s.skipLog.write(skipRecord{Team: team.Name, Reason: reason, Cells: cells})
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultSortKey ranks players when no -sort key is configured.
+const defaultSortKey = "potential"
+
+// sortField describes one sort key: the value it compares and whether its
+// natural ("best first") order is descending.
+type sortField struct {
+	value      func(p Player) float64
+	descending bool
+}
+
+// sortFields lists the supported sort keys. Every key's natural order puts
+// the "best" players first: highest ratings and growth, youngest age, lowest
+// price, most potential per unit of price.
+var sortFields = map[string]sortField{
+	"potential":        {func(p Player) float64 { return float64(p.Potential) }, true},
+	"growth":           {func(p Player) float64 { return float64(p.Growth) }, true},
+	"overall":          {func(p Player) float64 { return float64(p.Overall) }, true},
+	"age":              {func(p Player) float64 { return float64(p.Age) }, false},
+	"price":            {func(p Player) float64 { return float64(p.PriceValue) }, false},
+	"price_normalized": {func(p Player) float64 { return float64(p.PriceNormalized) }, false},
+	"value_ratio":      {func(p Player) float64 { return p.valueRank }, true},
+}
+
+// sortTerm is one parsed entry of a -sort list.
+type sortTerm struct {
+	field      sortField
+	descending bool
+}
+
+// parseSort parses a comma-separated list of sort keys such as
+// "potential,growth,age". Each key uses its natural order unless prefixed
+// with "-" (descending) or "+" (ascending).
+func parseSort(spec string) ([]sortTerm, error) {
+	var terms []sortTerm
+	for _, key := range strings.Split(spec, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+
+		name, prefix := key, byte(0)
+		if key[0] == '-' || key[0] == '+' {
+			name, prefix = key[1:], key[0]
+		}
+		field, ok := sortFields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort key %q (want one of %s, optionally prefixed with one - or +)", key, strings.Join(sortKeys(), ", "))
+		}
+
+		term := sortTerm{field: field, descending: field.descending}
+		switch prefix {
+		case '-':
+			term.descending = true
+		case '+':
+			term.descending = false
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("empty sort key list %q", spec)
+	}
+	return terms, nil
+}
+
+// sortKeys lists the supported sort keys in alphabetical order.
+func sortKeys() []string {
+	keys := make([]string, 0, len(sortFields))
+	for k := range sortFields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortPlayers orders players in place by the keys in spec, applied in turn
+// so later keys break ties left by earlier ones. Remaining ties keep their
+// existing order. spec must already have been validated with parseSort.
+func sortPlayers(players []Player, spec string) {
+	terms, err := parseSort(spec)
+	if err != nil {
+		return
+	}
+
+	sort.SliceStable(players, func(i, j int) bool {
+		for _, t := range terms {
+			c := cmp.Compare(t.field.value(players[i]), t.field.value(players[j]))
+			if c == 0 {
+				continue
+			}
+			if t.descending {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func sortFixture() []Player {
+	return []Player{
+		{Profile: "A", Potential: 80, Growth: 20, Age: 19},
+		{Profile: "B", Potential: 85, Growth: 10, Age: 20},
+		{Profile: "C", Potential: 80, Growth: 20, Age: 17},
+		{Profile: "D", Potential: 80, Growth: 25, Age: 21},
+		{Profile: "E", Potential: 85, Growth: 10, Age: 20},
+	}
+}
+
+func TestSortPlayersBreaksTies(t *testing.T) {
+	for spec, want := range map[string][]string{
+		// Highest potential, then highest growth, then youngest; B and E tie
+		// on every key and keep their order.
+		"potential,growth,age": {"B", "E", "D", "C", "A"},
+		"potential":            {"B", "E", "A", "C", "D"},
+		"potential,age":        {"B", "E", "C", "A", "D"},
+		"-age":                 {"D", "B", "E", "A", "C"},
+		"+potential,-age":      {"D", "A", "C", "B", "E"},
+		" growth , +age ":      {"D", "C", "A", "B", "E"},
+	} {
+		players := sortFixture()
+		sortPlayers(players, spec)
+		if got := profiles(players); !slices.Equal(got, want) {
+			t.Errorf("sort %q = %v, want %v", spec, got, want)
+		}
+	}
+}
+
+func TestParseSortRejects(t *testing.T) {
+	for _, spec := range []string{"", ",", "position", "--potential", "+-age", "-+growth", "-"} {
+		if _, err := parseSort(spec); err == nil {
+			t.Errorf("parseSort(%q) accepted", spec)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Statuses recorded for each source URL in the state store.
+const (
+	stateSuccess = "success"
+	stateFailed  = "failed"
+)
+
+// urlState is the last known outcome of scraping one source URL.
+type urlState struct {
+	Status        string    `json:"status"`
+	LastScrapedAt time.Time `json:"last_scraped_at"`
+	Error         string    `json:"error,omitempty"`
+
+	// Players holds what the last success kept, so -resume can carry them
+	// into the outputs when it skips the URL. Entries saved before players
+	// were stored have none and are never skipped.
+	Players []Player `json:"players"`
+}
+
+// stateStore tracks per-URL outcomes across runs so -resume can skip
+// sources that were scraped successfully not long ago.
+type stateStore struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]urlState
+}
+
+// loadState reads the state file at path. A missing file yields an empty store.
+func loadState(path string) (*stateStore, error) {
+	st := &stateStore{path: path, entries: make(map[string]urlState)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	if err := json.Unmarshal(data, &st.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	return st, nil
+}
+
+// resumable returns the players kept by the last scrape of url when it
+// succeeded within window of now.
+func (st *stateStore) resumable(url string, window time.Duration, now time.Time) ([]Player, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	e, ok := st.entries[url]
+	if !ok || e.Status != stateSuccess || now.Sub(e.LastScrapedAt) >= window || e.Players == nil {
+		return nil, false
+	}
+	return e.Players, true
+}
+
+// record stores the outcome of scraping url; errMsg is empty on success,
+// when players are the ones kept from the page.
+func (st *stateStore) record(url, errMsg string, players []Player, now time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	e := urlState{Status: stateSuccess, LastScrapedAt: now, Players: players}
+	if errMsg != "" {
+		e = urlState{Status: stateFailed, LastScrapedAt: now, Error: errMsg}
+	} else if e.Players == nil {
+		e.Players = []Player{}
+	}
+	st.entries[url] = e
+}
+
+// prune drops entries last scraped more than ttl before now.
+func (st *stateStore) prune(ttl time.Duration, now time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	for url, e := range st.entries {
+		if now.Sub(e.LastScrapedAt) > ttl {
+			delete(st.entries, url)
+		}
+	}
+}
+
+// save writes the store back to its file.
+func (st *stateStore) save() error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	data, err := json.MarshalIndent(st.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	return os.WriteFile(st.path, data, 0644)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStateResumable(t *testing.T) {
+	now := time.Now()
+	st := &stateStore{entries: make(map[string]urlState)}
+	st.record("ok", "", []Player{{Profile: "A"}}, now.Add(-time.Hour))
+	st.record("empty", "", nil, now.Add(-time.Hour))
+	st.record("stale", "", []Player{{Profile: "B"}}, now.Add(-48*time.Hour))
+	st.record("failed", "timeout", nil, now.Add(-time.Hour))
+	st.entries["old"] = urlState{Status: stateSuccess, LastScrapedAt: now.Add(-time.Hour)}
+
+	for url, want := range map[string]bool{"ok": true, "empty": true, "stale": false, "failed": false, "old": false, "unknown": false} {
+		if _, got := st.resumable(url, 24*time.Hour, now); got != want {
+			t.Errorf("resumable(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
+
+// A catch-up run that skips a fresh team must still write that team's
+// players, or the next -new-only run would report them all as new.
+func TestResumeKeepsSkippedTeamsPlayers(t *testing.T) {
+	var failB atomic.Bool
+	failB.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/b" && failB.Load() {
+			http.Error(w, "down", http.StatusNotFound)
+			return
+		}
+		name := map[string]string{"/a": "John Smith", "/b": "Tom Long"}[r.URL.Path]
+		_, _ = w.Write([]byte(`<table><tr><td>` + name + `</td><td>60</td><td>80</td><td>20</td><td>18</td><td>€1.2M</td></tr></table>`))
+	}))
+	defer srv.Close()
+	teams := []Team{{Name: "A", URL: srv.URL + "/a"}, {Name: "B", URL: srv.URL + "/b"}}
+	state := filepath.Join(t.TempDir(), "state.json")
+
+	first := newTestScraper(t)
+	first.stateFile = state
+	if err := first.Run(context.Background(), teams); err != nil {
+		t.Fatal(err)
+	}
+
+	failB.Store(false)
+	second := newTestScraper(t)
+	second.stateFile, second.resume = state, true
+	if err := second.Run(context.Background(), teams); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(second.outputs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var players []Player
+	if err := json.Unmarshal(data, &players); err != nil {
+		t.Fatal(err)
+	}
+	got := profiles(players)
+	sort.Strings(got)
+	if len(got) != 2 || got[0] != "John Smith" || got[1] != "Tom Long" {
+		t.Errorf("resumed output holds %v, want both teams' players", got)
+	}
+	if stats := second.stats.Snapshot(); stats.Requests != 1 {
+		t.Errorf("resumed run made %d requests, want 1", stats.Requests)
+	}
+}
+
+// A run that withholds its outputs because the layout changed must not leave
+// state behind that makes the next -resume run skip every team and
+// overwrite the good output with nothing.
+func TestResumeAfterLayoutChange(t *testing.T) {
+	var broken atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if broken.Load() || r.URL.Path == "/empty" {
+			_, _ = w.Write([]byte(`<html><div class="roster">coming soon</div></html>`))
+			return
+		}
+		_, _ = w.Write([]byte(rosterPage))
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	state, output := filepath.Join(dir, "state.json"), filepath.Join(dir, "players.json")
+
+	run := func(resume bool, teams []Team) error {
+		s := newTestScraper(t)
+		s.stateFile, s.resume, s.outputs = state, resume, []string{output}
+		return s.Run(context.Background(), teams)
+	}
+	teams := []Team{{Name: "A", URL: srv.URL + "/a"}}
+	if err := run(false, teams); err != nil {
+		t.Fatal(err)
+	}
+	broken.Store(true)
+	if err := run(false, teams); !errors.Is(err, errLayoutChanged) {
+		t.Fatalf("broken run: %v, want errLayoutChanged", err)
+	}
+	broken.Store(false)
+	if err := run(true, teams); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var players []Player
+	if err := json.Unmarshal(data, &players); err != nil {
+		t.Fatal(err)
+	}
+	if len(players) == 0 {
+		t.Error("the resumed run overwrote the output with no players")
+	}
+
+	// A page without rows in a run that did write its outputs is still not
+	// a result to resume from.
+	if err := run(false, append(teams, Team{Name: "B", URL: srv.URL + "/empty"})); err != nil {
+		t.Fatal(err)
+	}
+	st, err := loadState(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := st.resumable(srv.URL+"/empty", time.Hour, time.Now()); ok {
+		t.Error("a page without rows was recorded as resumable")
+	}
+}
+
+// Nor is state saved when -on-missing=error withholds the outputs.
+func TestStateNotSavedWhenOutputsWithheld(t *testing.T) {
+	srv := servePage(t, rosterPage)
+	s := newTestScraper(t)
+	s.stateFile = filepath.Join(t.TempDir(), "state.json")
+	s.require, s.onMissing = []string{"height"}, missingError
+	if err := s.Run(context.Background(), []Team{{Name: "A", URL: srv.URL}}); err == nil {
+		t.Fatal("Run() succeeded with players missing a required field")
+	}
+	if _, err := os.Stat(s.stateFile); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("state file was saved: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunStats holds the counters gathered during one run.
+type RunStats struct {
+	Requests       int64         `json:"requests"`
+	FailedRequests int64         `json:"failed_requests"`
+	Retries        int64         `json:"retries"`
+	TeamsSucceeded int64         `json:"teams_succeeded"`
+	TeamsFailed    int64         `json:"teams_failed"`
+	SparseTeams    int64         `json:"sparse_teams"` // Teams with fewer rows than -min-players-per-team.
+	PagesWithBody  int64         `json:"pages_with_body"`
+	RowsSeen       int64         `json:"rows_seen"`
+	ParseFailures  int64         `json:"parse_failures"`
+	SalvagedRows   int64         `json:"salvaged_rows"` // Rows skipped after a panic while parsing them.
+	PlayersKept    int64         `json:"players_kept"`
+	FetchTime      time.Duration `json:"fetch_time_ns"` // Summed over all requests.
+
+	// AttemptsHistogram counts teams by the number of attempts they needed.
+	AttemptsHistogram map[int]int64 `json:"attempts_histogram"`
+
+	// MissingFields counts, per -require field, the players that lacked it.
+	MissingFields map[string]int64 `json:"missing_fields,omitempty"`
+}
+
+// Stats accumulates RunStats from concurrent workers. All access goes
+// through the mutex so the counters stay consistent with each other.
+type Stats struct {
+	mu      sync.Mutex
+	current RunStats
+}
+
+// update applies fn to the counters while holding the lock.
+func (st *Stats) update(fn func(*RunStats)) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	fn(&st.current)
+}
+
+// Snapshot returns a consistent copy of the counters.
+func (st *Stats) Snapshot() RunStats {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	snap := st.current
+	snap.AttemptsHistogram = make(map[int]int64, len(st.current.AttemptsHistogram))
+	for attempts, teams := range st.current.AttemptsHistogram {
+		snap.AttemptsHistogram[attempts] = teams
+	}
+	snap.MissingFields = maps.Clone(st.current.MissingFields)
+	return snap
+}
+
+// recordAttempts adds a team that needed the given number of attempts to
+// the histogram. Teams that made no requests are not counted.
+func (st *Stats) recordAttempts(attempts int) {
+	if attempts == 0 {
+		return
+	}
+	st.update(func(rs *RunStats) {
+		if rs.AttemptsHistogram == nil {
+			rs.AttemptsHistogram = make(map[int]int64)
+		}
+		rs.AttemptsHistogram[attempts]++
+	})
+}
+
+// formatHistogram renders the attempts histogram as "1 attempt: 20 teams, 2 attempts: 3 teams".
+func formatHistogram(h map[int]int64) string {
+	attempts := make([]int, 0, len(h))
+	for a := range h {
+		attempts = append(attempts, a)
+	}
+	sort.Ints(attempts)
+
+	parts := make([]string, len(attempts))
+	for i, a := range attempts {
+		parts[i] = fmt.Sprintf("%d %s: %d %s", a, plural(a, "attempt"), h[a], plural(int(h[a]), "team"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// plural appends an "s" to word unless n is one.
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
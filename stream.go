@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// streamed reports whether a response body of the given length (-1 when
+// unknown, e.g. chunked or gzip-compressed) should be parsed while it is
+// read rather than buffered whole. Dumps and captures always need the full page.
+func (s *Scraper) streamed(contentLength int64) bool {
+	if s.streamAbove <= 0 || s.dumpDir != "" || s.fetchOnly != "" {
+		return false
+	}
+	return contentLength < 0 || contentLength > s.streamAbove
+}
+
+// rowParser turns the table rows of one team page into players, a row at a
+// time, so a streamed page is parsed while it is still being read.
+type rowParser struct {
+	s       *Scraper
+	team    Team
+	kept    map[string]bool // Rows already kept; a repeated row is only kept once.
+	players []Player
+	rows    int  // Player rows seen before any filtering.
+	fed     bool // The rows were handed over while the page was read.
+}
+
+func (s *Scraper) newRowParser(team Team) *rowParser {
+	return &rowParser{s: s, team: team, kept: make(map[string]bool)}
+}
+
+// reset forgets the rows of an earlier, failed attempt at the page.
+func (rp *rowParser) reset() {
+	clear(rp.kept)
+	rp.players, rp.rows, rp.fed = nil, 0, false
+}
+
+// add parses one row.
+func (rp *rowParser) add(row string) {
+	p, isPlayer, keep := rp.s.safeParseRow(rp.team, row, rp.kept)
+	if isPlayer {
+		rp.rows++
+	}
+	if keep {
+		rp.players = append(rp.players, p)
+	}
+}
+
+// result returns the players kept and the number of player rows seen, and
+// counts those rows in the run stats.
+func (rp *rowParser) result() ([]Player, int) {
+	rp.s.stats.update(func(rs *RunStats) { rs.RowsSeen += int64(rp.rows) })
+	return rp.players, rp.rows
+}
+
+// streamRows decodes a page to UTF-8 as it is read and hands each of its
+// rows to rows as soon as the row is complete. Nothing else of the page is
+// kept, so memory is bounded by one row rather than the whole document. It
+// returns the number of non-whitespace bytes read.
+func streamRows(r io.Reader, contentType string, rows *rowParser) (int64, error) {
+	decoded, err := charset.NewReader(r, contentType)
+	if errors.Is(err, io.EOF) {
+		return 0, nil // An empty body.
+	}
+	if err != nil {
+		return 0, err
+	}
+	rows.fed = true
+	return eachRow(decoded, rows.add)
+}
+
+// eachRow tokenizes the HTML read from r and calls fn with the markup of
+// every table row as soon as its </tr> is read. A row left open by the next
+// <tr>, the end of its table or the end of the page is handed over as it
+// stands, the way a browser closes it, so no row is lost. It also returns
+// the number of non-whitespace bytes read, so a page that had content but
+// no rows can be told apart from an empty one.
+func eachRow(r io.Reader, fn func(row string)) (int64, error) {
+	z := html.NewTokenizer(r)
+	var row strings.Builder
+	open := false
+	flush := func() {
+		if open {
+			fn(row.String())
+			row.Reset()
+			open = false
+		}
+	}
+
+	var content int64
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); !errors.Is(err, io.EOF) {
+				return content, err
+			}
+			flush()
+			return content, nil
+		}
+		raw := z.Raw()
+		content += contentBytes(raw)
+
+		if tt == html.StartTagToken || tt == html.EndTagToken {
+			switch name, _ := z.TagName(); string(name) {
+			case "tr":
+				if tt == html.StartTagToken {
+					flush()
+					open = true
+				} else if open {
+					row.Write(raw)
+					flush()
+					continue
+				}
+			case "table", "tbody", "thead", "tfoot":
+				flush()
+			}
+		}
+		if open {
+			row.Write(raw)
+		}
+	}
+}
+
+// contentBytes counts the bytes of data that are not ASCII whitespace.
+func contentBytes(data []byte) int64 {
+	var n int64
+	for _, b := range data {
+		switch b {
+		case ' ', '\t', '\n', '\r', '\f', '\v':
+		default:
+			n++
+		}
+	}
+	return n
+}
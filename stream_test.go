@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEachRowKeepsRows(t *testing.T) {
+	var rows []string
+	content, err := eachRow(strings.NewReader(rosterPage), func(row string) { rows = append(rows, row) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || !strings.HasPrefix(rows[1], "<tr><td><a href=\"/p/1\">John Smith</a>") || !strings.HasSuffix(rows[1], "</tr>") {
+		t.Errorf("rows = %q, want the header and 3 player rows", rows)
+	}
+	if want := contentBytes([]byte(rosterPage)); content != want {
+		t.Errorf("content = %d, want %d", content, want)
+	}
+}
+
+func TestEachRowReportsContentWithoutRows(t *testing.T) {
+	calls := 0
+	content, err := eachRow(strings.NewReader("<html><div>New layout</div></html>\n"), func(string) { calls++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Errorf("handed over %d rows, want none", calls)
+	}
+	if content == 0 {
+		t.Error("content = 0 for a page with markup")
+	}
+}
+
+// Rows that are not closed, span lines or share one enormous line are all
+// handed over whole.
+func TestEachRowKeepsUnusualRows(t *testing.T) {
+	cells := "<td>60</td><td>80</td><td>20</td><td>18</td><td>€1.2M</td>"
+	var oneLine strings.Builder
+	for i := range 20000 {
+		fmt.Fprintf(&oneLine, "<tr><td>Player %d</td>%s</tr>", i, cells)
+	}
+	for _, tc := range []struct {
+		name, page string
+		want       int
+	}{
+		{"unclosed", "<table><tr><td>A</td>" + cells + "<tr><td>B</td>" + cells + "</table>", 2},
+		{"unclosed at the end", "<table><tr><td>A</td>" + cells, 1},
+		{"across lines", "<table><tr>\n<td>A</td>\n" + strings.ReplaceAll(cells, "</td>", "</td>\n") + "</tr></table>", 1},
+		{"one line", "<table>" + oneLine.String() + "</table>", 20000},
+	} {
+		s := newTestScraper(t)
+		players, rows := s.extractPlayers(Team{Name: "A"}, tc.page)
+		if rows != tc.want || len(players) != tc.want {
+			t.Errorf("%s: got %d players from %d rows, want %d", tc.name, len(players), rows, tc.want)
+		}
+	}
+}
+
+// A chunked page is parsed while it is read and gives the same players as
+// the buffered path.
+func TestStreamedMatchesBuffered(t *testing.T) {
+	var page strings.Builder
+	page.WriteString("<html><head><meta charset=\"iso-8859-1\"></head><table>\n")
+	for i := range 5000 {
+		fmt.Fprintf(&page, "<tr><td>Jos\xe9 %d</td><td>60</td><td>80</td><td>20</td><td>18</td><td>\x801.2M</td></tr>\n", i)
+	}
+	page.WriteString("</table></html>\n")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		body := page.String()
+		for len(body) > 0 {
+			n := min(len(body), 1000)
+			_, _ = w.Write([]byte(body[:n]))
+			w.(http.Flusher).Flush()
+			body = body[n:]
+		}
+	}))
+	defer srv.Close()
+	team := Team{Name: "A", URL: srv.URL}
+
+	s := newTestScraper(t)
+	buffered, _ := s.extractPlayers(team, s.decodeBody([]byte(page.String()), "text/html"))
+	rows := s.newRowParser(team)
+	if _, _, err := s.fetchHTML(context.Background(), team, team.URL, rows); err != nil {
+		t.Fatal(err)
+	}
+	if !rows.fed {
+		t.Fatal("a chunked page was not streamed")
+	}
+	streamed, _ := rows.result()
+	if len(buffered) != 5000 || len(streamed) != len(buffered) {
+		t.Fatalf("streamed %d players, buffered %d", len(streamed), len(buffered))
+	}
+	if streamed[0].Profile != "José 0" || streamed[0].Price != "€1.2M" || streamed[4999].String() != buffered[4999].String() {
+		t.Errorf("streamed %v, buffered %v", streamed[0], buffered[0])
+	}
+}
+
+// A chunked page has no Content-Length, so it is streamed by default; a
+// changed layout must still be reported rather than written as no players.
+func TestStreamedPageWithoutRowsIsLayoutChange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html><div class=\"roster-v2\">"))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte("players moved</div></html>\n"))
+	}))
+	defer srv.Close()
+
+	for _, above := range []int64{0, 4 << 20} {
+		s := newTestScraper(t)
+		s.streamAbove = above
+		err := s.Run(context.Background(), []Team{{Name: "A", URL: srv.URL + "/team/a"}})
+		if !errors.Is(err, errLayoutChanged) {
+			t.Errorf("-stream-above=%d: Run() = %v, want %v", above, err, errLayoutChanged)
+		}
+	}
+}
+
+// A streamed attempt cut off part way is retried from scratch: the rows it
+// had already handed over are not counted twice.
+func TestStreamedRetryStartsOver(t *testing.T) {
+	var page strings.Builder
+	page.WriteString("<html><table>\n")
+	for i := range 100 {
+		fmt.Fprintf(&page, "<tr><td>Player %d</td><td>60</td><td>80</td><td>20</td><td>18</td><td>€1.2M</td></tr>\n", i)
+	}
+	page.WriteString("</table></html>\n")
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			_, _ = w.Write([]byte(page.String()[:page.Len()/2]))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		_, _ = w.Write([]byte(page.String()))
+	}))
+	defer srv.Close()
+
+	s := newTestScraper(t)
+	s.retries = 1
+	players, status := s.processTeam(context.Background(), Team{Name: "A", URL: srv.URL})
+	if status.Error != "" || status.Attempts != 2 {
+		t.Fatalf("status = %+v, want success on the second attempt", status)
+	}
+	if len(players) != 100 || status.Rows != 100 || s.stats.Snapshot().RowsSeen != 100 {
+		t.Errorf("got %d players from %d rows (%d in the stats), want 100", len(players), status.Rows, s.stats.Snapshot().RowsSeen)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// TeamStatus records how scraping one team went. Rows counts the player rows
+// found on the page before filtering; Players counts those that were kept.
+type TeamStatus struct {
+	Team     string `json:"team"`
+	URL      string `json:"url"`
+	Attempts int    `json:"attempts"`
+	Rows     int    `json:"rows"`
+	Players  int    `json:"players"`
+	Error    string `json:"error,omitempty"`
+	Skipped  bool   `json:"skipped,omitempty"` // Left out by -resume because it is still fresh.
+}
+
+// RunSummary describes a finished run. It is kept separate from the player
+// output so per-run metadata is not repeated in every player record.
+type RunSummary struct {
+	StartedAt time.Time     `json:"started_at"`
+	Duration  string        `json:"duration"`
+	Stats     RunStats      `json:"stats"`
+	Players   int           `json:"players"`
+	Bands     []OverallBand `json:"overall_bands"` // Players kept per overall-rating band.
+	Teams     []TeamStatus  `json:"teams"`
+}
+
+// writeSummary saves the run summary as indented JSON.
+func writeSummary(path string, summary RunSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary to JSON: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
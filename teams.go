@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// teamList returns the teams to scrape: the built-in list, or the one read
+// from the -teams source ("-" for stdin).
+func (s *Scraper) teamList() ([]Team, error) {
+	if s.teamsFrom == "" {
+		return teams, nil
+	}
+	if s.teamsFrom == "-" {
+		return parseTeams(os.Stdin)
+	}
+
+	f, err := os.Open(s.teamsFrom)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open team list: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return parseTeams(f)
+}
+
+// selectTeams narrows list down to the teams this run should scrape. It
+// applies -only first, then -shuffle, then -max-teams, so "-shuffle
+// -max-teams=2" scrapes a random sample of two.
+func (s *Scraper) selectTeams(list []Team) ([]Team, error) {
+	if len(s.only) > 0 {
+		wanted := make(map[string]bool, len(s.only))
+		for _, name := range s.only {
+			wanted[strings.ToLower(strings.TrimSpace(name))] = true
+		}
+
+		var picked []Team
+		for _, t := range list {
+			if wanted[strings.ToLower(t.Name)] {
+				picked = append(picked, t)
+				delete(wanted, strings.ToLower(t.Name))
+			}
+		}
+		for name := range wanted {
+			log.Printf("Warning: -only team %q is not in the team list\n", name)
+		}
+		if len(picked) == 0 {
+			return nil, errors.New("no team matches -only")
+		}
+		list = picked
+	}
+
+	if s.shuffle {
+		list = append([]Team(nil), list...)
+		s.withRand(func(r *rand.Rand) {
+			r.Shuffle(len(list), func(i, j int) {
+				list[i], list[j] = list[j], list[i]
+			})
+		})
+	}
+
+	if s.maxTeams > 0 && len(list) > s.maxTeams {
+		list = list[:s.maxTeams]
+	}
+	return list, nil
+}
+
+// Dispatch orders accepted by -dispatch-order.
+const (
+	dispatchInOrder    = "in-order"    // Teams are fetched in list order.
+	dispatchRoundRobin = "round-robin" // Teams are interleaved across hosts.
+)
+
+// dispatchOrder returns the indexes of teams in the order they should be
+// fetched. With round-robin, teams are grouped by host (in order of first
+// appearance) and taken one from each host in turn, so consecutive requests
+// go to different hosts; within a host the list order is kept.
+func (s *Scraper) dispatchOrder(teams []Team) []int {
+	order := make([]int, 0, len(teams))
+	if s.dispatch != dispatchRoundRobin {
+		for i := range teams {
+			order = append(order, i)
+		}
+		return order
+	}
+
+	var hosts []string
+	byHost := make(map[string][]int)
+	for i, t := range teams {
+		host := ""
+		if u, err := url.Parse(t.URL); err == nil {
+			host = u.Host
+		}
+		if _, ok := byHost[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		byHost[host] = append(byHost[host], i)
+	}
+
+	for len(order) < len(teams) {
+		for _, host := range hosts {
+			if queue := byHost[host]; len(queue) > 0 {
+				order = append(order, queue[0])
+				byHost[host] = queue[1:]
+			}
+		}
+	}
+	return order
+}
+
+// parseTeams reads a team list as JSON or as "name,url" lines. The format is
+// detected from the first non-space byte: '[' or '{' means JSON.
+func parseTeams(r io.Reader) ([]Team, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err == io.EOF {
+			return nil, errors.New("team list is empty")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read team list: %w", err)
+		}
+		if strings.TrimSpace(string(b)) != "" {
+			break
+		}
+		_, _ = br.ReadByte()
+	}
+
+	b, _ := br.Peek(1)
+	var (
+		list []Team
+		err  error
+	)
+	if b[0] == '[' || b[0] == '{' {
+		list, err = parseTeamsJSON(br)
+	} else {
+		list, err = parseTeamsCSV(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, errors.New("team list is empty")
+	}
+	return list, nil
+}
+
+// parseTeamsJSON accepts either a JSON array of teams or a stream of team
+// objects, one after another (e.g. one per line).
+func parseTeamsJSON(r *bufio.Reader) ([]Team, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var list []Team
+	if b, _ := r.Peek(1); b[0] == '[' {
+		if err := dec.Decode(&list); err != nil {
+			return nil, fmt.Errorf("malformed JSON team list: %w", err)
+		}
+	} else {
+		for {
+			var t Team
+			err := dec.Decode(&t)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("malformed JSON team list entry %d: %w", len(list)+1, err)
+			}
+			list = append(list, t)
+		}
+	}
+
+	for i, t := range list {
+		if err := t.check(); err != nil {
+			return nil, fmt.Errorf("team list entry %d: %w", i+1, err)
+		}
+	}
+	return list, nil
+}
+
+// parseTeamsCSV reads "name,url" records, one per line. Blank lines and lines
+// starting with '#' are ignored.
+func parseTeamsCSV(r io.Reader) ([]Team, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+
+	var list []Team
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("malformed team list (want name,url per line): %w", err)
+		}
+
+		t := Team{Name: strings.TrimSpace(record[0]), URL: strings.TrimSpace(record[1])}
+		if err := t.check(); err != nil {
+			line, _ := cr.FieldPos(0)
+			return nil, fmt.Errorf("team list line %d: %w", line, err)
+		}
+		list = append(list, t)
+	}
+	return list, nil
+}
+
+// check reports whether the team has the fields needed to scrape it.
+func (t Team) check() error {
+	if t.Name == "" {
+		return errors.New("missing team name")
+	}
+	u, err := url.Parse(t.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL %q for %s", t.URL, t.Name)
+	}
+	if (t.MinDelay != nil && *t.MinDelay < 0) || (t.MaxDelay != nil && *t.MaxDelay < 0) {
+		return fmt.Errorf("negative delay for %s", t.Name)
+	}
+	if t.MinDelay != nil && t.MaxDelay != nil && *t.MinDelay > *t.MaxDelay {
+		return fmt.Errorf("min_delay %v is above max_delay %v for %s", *t.MinDelay, *t.MaxDelay, t.Name)
+	}
+	return nil
+}
+
+// delays returns the delay range for requests to the team: its own bounds
+// where set, the given defaults otherwise. If only one bound is overridden
+// and the range ends up inverted, the overridden bound wins for both.
+func (t Team) delays(defaultMin, defaultMax time.Duration) (time.Duration, time.Duration) {
+	minDelay, maxDelay := defaultMin, defaultMax
+	if t.MinDelay != nil {
+		minDelay = time.Duration(*t.MinDelay)
+	}
+	if t.MaxDelay != nil {
+		maxDelay = time.Duration(*t.MaxDelay)
+	}
+	if minDelay > maxDelay {
+		if t.MinDelay != nil {
+			maxDelay = minDelay
+		} else {
+			minDelay = maxDelay
+		}
+	}
+	return minDelay, maxDelay
+}
+
+// Duration is a time.Duration that is written in JSON as a string such as
+// "1.5s" or "500ms".
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be a string such as \"1.5s\": %w", err)
+	}
+	v, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// hangingServer never answers; requests end only when the client gives up.
+func hangingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+	return srv
+}
+
+// Each test sets one bound and disables the others, so whichever fires is
+// the one under test.
+func timeoutScraper(t *testing.T) *Scraper {
+	t.Helper()
+	s := newTestScraper(t)
+	s.requestTimeout, s.teamTimeout, s.totalTimeout = 0, 0, 0
+	s.retries = 2
+	return s
+}
+
+// A request timeout fails the request, which is then retried.
+func TestRequestTimeout(t *testing.T) {
+	srv := hangingServer(t)
+	s := timeoutScraper(t)
+	s.requestTimeout = 50 * time.Millisecond
+
+	started := time.Now()
+	_, status := s.processTeam(context.Background(), Team{Name: "A", URL: srv.URL})
+	if status.Error == "" {
+		t.Fatal("team did not fail")
+	}
+	if status.Attempts != 3 {
+		t.Errorf("made %d attempts, want 3", status.Attempts)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("took %v", elapsed)
+	}
+}
+
+// A team timeout ends all work on the team, retries included.
+func TestTeamTimeout(t *testing.T) {
+	srv := hangingServer(t)
+	s := timeoutScraper(t)
+	s.teamTimeout = 50 * time.Millisecond
+
+	started := time.Now()
+	_, status := s.processTeam(context.Background(), Team{Name: "A", URL: srv.URL})
+	if status.Error == "" {
+		t.Fatal("team did not fail")
+	}
+	if status.Attempts != 1 {
+		t.Errorf("made %d attempts, want 1", status.Attempts)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("took %v", elapsed)
+	}
+}
+
+// A total timeout ends the run: the team in flight fails and the rest are
+// not started.
+func TestTotalTimeout(t *testing.T) {
+	srv := hangingServer(t)
+	s := timeoutScraper(t)
+	s.totalTimeout = 50 * time.Millisecond
+	s.concurrency = 1
+	teams := []Team{{Name: "A", URL: srv.URL + "/a"}, {Name: "B", URL: srv.URL + "/b"}}
+
+	started := time.Now()
+	if err := s.Run(context.Background(), teams); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("took %v", elapsed)
+	}
+	stats := s.stats.Snapshot()
+	if stats.Requests != 1 || stats.TeamsFailed != 1 {
+		t.Errorf("made %d requests with %d failed teams, want 1 and 1", stats.Requests, stats.TeamsFailed)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"log"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// traceRequest attaches an httptrace.ClientTrace to ctx for -http-trace,
+// logging DNS, connect, TLS handshake and first-byte timings of the request
+// to url. The returned func logs the total time and should run once the
+// body has been read. Without -http-trace, ctx is returned unchanged.
+func (s *Scraper) traceRequest(ctx context.Context, url string) (context.Context, func()) {
+	if !s.httpTrace {
+		return ctx, func() {}
+	}
+
+	var (
+		mu                            sync.Mutex // Dial attempts may report concurrently.
+		start                         = time.Now()
+		dnsStart, connStart, tlsStart time.Time
+	)
+	logf := func(format string, args ...any) {
+		log.Printf("DEBUG trace %s: "+format+"\n", append([]any{redactURL(url)}, args...)...)
+	}
+	since := func(t *time.Time) time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		return time.Since(*t)
+	}
+	mark := func(t *time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		*t = time.Now()
+	}
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { mark(&dnsStart) },
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			logf("DNS lookup %v (err: %v)", since(&dnsStart), info.Err)
+		},
+		ConnectStart: func(network, addr string) { mark(&connStart) },
+		ConnectDone: func(network, addr string, err error) {
+			logf("connect to %s %v (err: %v)", addr, since(&connStart), err)
+		},
+		TLSHandshakeStart: func() { mark(&tlsStart) },
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			logf("TLS handshake %v (%s, err: %v)", since(&tlsStart), state.NegotiatedProtocol, err)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			logf("got connection after %v (reused: %v)", since(&start), info.Reused)
+		},
+		GotFirstResponseByte: func() {
+			logf("first byte after %v", since(&start))
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace), func() {
+		logf("total %v", since(&start))
+	}
+}
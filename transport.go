@@ -0,0 +1,25 @@
+package main
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// configureTransport installs a transport restricted to HTTP/1.1 when -http1
+// is set. Otherwise the default transport negotiates HTTP/2 where the server
+// supports it.
+func (s *Scraper) configureTransport() {
+	if !s.http1 || s.client.Transport != nil {
+		return
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ForceAttemptHTTP2 = false
+	// A non-nil, empty map disables the transport's HTTP/2 upgrade.
+	t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	t.TLSClientConfig.NextProtos = []string{"http/1.1"}
+	s.client.Transport = t
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// buildURL adds params to base, keeping any query string and path it
+// already has, with or without a trailing slash. Existing parameters with the
+// same name are replaced. The query is encoded sorted by key, so the same
+// inputs always give the same URL.
+func buildURL(base string, params map[string]string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", base, err)
+	}
+	if len(params) == 0 {
+		return u.String(), nil
+	}
+
+	query := u.Query()
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestBuildURL(t *testing.T) {
+	params := map[string]string{"currency": "EUR"}
+	for base, want := range map[string]string{
+		"https://fifacm.com/team/1":                   "https://fifacm.com/team/1?currency=EUR",
+		"https://fifacm.com/team/1/":                  "https://fifacm.com/team/1/?currency=EUR",
+		"https://fifacm.com/team/1?year=24":           "https://fifacm.com/team/1?currency=EUR&year=24",
+		"https://fifacm.com/team/1/?year=24&page=2":   "https://fifacm.com/team/1/?currency=EUR&page=2&year=24",
+		"https://fifacm.com/team/1?currency=GBP":      "https://fifacm.com/team/1?currency=EUR",
+		"https://fifacm.com/team/1?name=a%20b#roster": "https://fifacm.com/team/1?currency=EUR&name=a+b#roster",
+	} {
+		got, err := buildURL(base, params)
+		if err != nil {
+			t.Errorf("buildURL(%q): %v", base, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("buildURL(%q) = %q, want %q", base, got, want)
+		}
+	}
+}
+
+func TestBuildURLWithoutParams(t *testing.T) {
+	const base = "https://fifacm.com/team/1/?year=24"
+	if got, err := buildURL(base, nil); err != nil || got != base {
+		t.Errorf("buildURL(%q, nil) = %q, %v", base, got, err)
+	}
+	if _, err := buildURL("://bad", nil); err == nil {
+		t.Error("accepted an invalid URL")
+	}
+}
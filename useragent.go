@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+)
+
+// User-agent rotation policies accepted by -ua-policy.
+const (
+	uaPerRequest = "per-request" // A new user agent for every request.
+	uaPerHost    = "per-host"    // One user agent per host for the whole run.
+	uaPerRun     = "per-run"     // One user agent for every request in the run.
+)
+
+// validateUAPolicy checks that policy is one of the supported values.
+func validateUAPolicy(policy string) error {
+	switch policy {
+	case uaPerRequest, uaPerHost, uaPerRun:
+		return nil
+	}
+	return fmt.Errorf("unknown user-agent policy %q (want %s, %s or %s)", policy, uaPerRequest, uaPerHost, uaPerRun)
+}
+
+// userAgentFor returns the user agent to send to rawURL under the configured policy.
+func (s *Scraper) userAgentFor(rawURL string) string {
+	s.uaMu.Lock()
+	defer s.uaMu.Unlock()
+
+	var key string
+	switch s.uaPolicy {
+	case uaPerHost:
+		if u, err := url.Parse(rawURL); err == nil {
+			key = u.Host
+		}
+	case uaPerRun:
+		key = "*"
+	default:
+		return s.randomAgent()
+	}
+
+	if ua, ok := s.stickyAgents[key]; ok {
+		return ua
+	}
+	ua := s.randomAgent()
+	s.stickyAgents[key] = ua
+	return ua
+}
+
+// resetAgents forgets the pinned user agents, so the per-run and per-host
+// policies pick fresh ones for each run of a -poll loop.
+func (s *Scraper) resetAgents() {
+	s.uaMu.Lock()
+	defer s.uaMu.Unlock()
+	clear(s.stickyAgents)
+}
+
+// randomAgent picks one of the user agents at random.
+func (s *Scraper) randomAgent() string {
+	var i int
+	s.withRand(func(r *rand.Rand) { i = r.Intn(len(userAgents)) })
+	return userAgents[i]
+}
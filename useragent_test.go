@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUserAgentPolicies(t *testing.T) {
+	s := newTestScraper(t)
+	s.uaPolicy = uaPerHost
+	a := s.userAgentFor("https://a.example/team/1")
+	for range 20 {
+		if got := s.userAgentFor("https://a.example/team/2"); got != a {
+			t.Fatalf("per-host: got %q, then %q for the same host", a, got)
+		}
+	}
+
+	s.uaPolicy = uaPerRun
+	run := s.userAgentFor("https://a.example/")
+	if got := s.userAgentFor("https://b.example/"); got != run {
+		t.Errorf("per-run: got %q, then %q", run, got)
+	}
+}
+
+// Each Run starts with no pinned user agents, so a -poll loop does not keep
+// one for the life of the process.
+func TestRunResetsPinnedAgents(t *testing.T) {
+	s := newTestScraper(t)
+	s.uaPolicy = uaPerRun
+	s.userAgentFor("https://a.example/")
+	if len(s.stickyAgents) != 1 {
+		t.Fatalf("pinned %d agents, want 1", len(s.stickyAgents))
+	}
+	if err := s.Run(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.stickyAgents) != 0 {
+		t.Errorf("%d agents still pinned after a new run", len(s.stickyAgents))
+	}
+}